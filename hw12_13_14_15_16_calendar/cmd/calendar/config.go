@@ -1,21 +1,89 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+	internalhttp "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/http"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
 )
 
 type Config struct {
-	Logger  LoggerConf  `toml:"logger"`
-	Server  ServerConf  `toml:"server"`
-	Storage StorageConf `toml:"storage"`
-	DB      DBConf      `toml:"db"`
+	Logger    cmdutil.LoggerConf `yaml:"logger"`
+	Server    ServerConf         `yaml:"server"`
+	Storage   StorageConf        `yaml:"storage"`
+	DB        cmdutil.DBConf     `yaml:"db"`
+	Tracing   TracingConf        `yaml:"tracing"`
+	Admin     AdminConf          `yaml:"admin"`
+	Reload    ReloadConf         `yaml:"reload"`
+	Auth      AuthConf           `yaml:"auth"`
+	RateLimit RateLimitConf      `yaml:"rate_limit"`
+	CORS      CORSConf           `yaml:"cors"`
 }
 
-type LoggerConf struct {
-	Level string `toml:"level"`
+// CORSConf - вызовы HTTP API из браузера с других origin, перезагружается без рестарта.
+// AllowedOrigins - "https://app.example.com", "https://*.example.com" или "*".
+// Пустые AllowedMethods и AllowedHeaders - GET, POST, PUT, DELETE и заголовки,
+// которые читает API (Authorization, Content-Type, X-API-Key, X-Request-ID).
+type CORSConf struct {
+	Enabled          bool          `yaml:"enabled"`
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// AuthConf - аутентификация запросов к /api/ и EventService. Токен передаётся в заголовке
+// Authorization: Bearer (метаданные authorization в gRPC), API ключ - там же или в X-API-Key.
+type AuthConf struct {
+	Enabled bool    `yaml:"enabled"`
+	JWT     JWTConf `yaml:"jwt"`
+	// статические ключи: субъект -> ключ
+	APIKeys map[string]string `yaml:"api_keys" secret:"true"`
+}
+
+// RateLimitConf - ограничение частоты запросов к /api/ и EventService для каждого клиента:
+// аутентифицированного пользователя, а без аутентификации - IP адреса (X-Forwarded-For
+// учитывается только от server.trusted_proxies). Rate - запросов в секунду, Burst - подряд.
+// Routes - отдельные правила: "POST /api/events", "/api/events/day" или "/event.EventService/CreateEvent".
+// AuthFailures - неудачные попытки аутентификации с одного IP, без rate - по общему правилу.
+type RateLimitConf struct {
+	Enabled      bool                    `yaml:"enabled"`
+	Rate         float64                 `yaml:"rate"`
+	Burst        int                     `yaml:"burst"`
+	Routes       map[string]RateRuleConf `yaml:"routes"`
+	AuthFailures RateRuleConf            `yaml:"auth_failures"`
+}
+
+type RateRuleConf struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type JWTConf struct {
+	HS256Secret string        `yaml:"hs256_secret" secret:"true"`
+	JWKSFile    string        `yaml:"jwks_file"` // открытые ключи RS256
+	Issuer      string        `yaml:"issuer"`
+	Audience    string        `yaml:"audience"`
+	Leeway      time.Duration `yaml:"leeway"`
+}
+
+// AdminConf - служебный HTTP сервер (уровень логирования); при нулевом порте не запускается.
+type AdminConf struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// ReloadConf - перечитывание конфигурации без перезапуска. По SIGHUP конфигурация
+// перечитывается всегда, при WatchInterval > 0 файл ещё и проверяется на изменения.
+type ReloadConf struct {
+	WatchInterval time.Duration `yaml:"watch_interval"`
 }
 
 type ServerConf struct {
@@ -24,51 +92,230 @@ type ServerConf struct {
 	GRPCPort int    `yaml:"grpc_port"`
 	// Для обратной совместимости
 	Port int `yaml:"port"`
+	// пауза между снятием готовности и остановкой серверов,
+	// чтобы балансировщик успел исключить экземпляр
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	TLS           TLSConf       `yaml:"tls"`
+	Gzip          GzipConf      `yaml:"gzip"`
+	// MaxBodySize - ограничение размера тела запроса к HTTP API в байтах, 0 - 1 МБ
+	MaxBodySize int64 `yaml:"max_body_size"`
+	// TrustedProxies - адреса и подсети прокси, чей X-Forwarded-For определяет адрес клиента
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// GzipConf - сжатие ответов HTTP клиентам с Accept-Encoding: gzip, начиная с MinSize байт.
+type GzipConf struct {
+	Enabled bool `yaml:"enabled"`
+	MinSize int  `yaml:"min_size"`
+}
+
+// TLSConf - HTTPS и gRPC поверх TLS. Сертификаты перечитываются по SIGHUP и, при
+// ReloadInterval > 0, при изменении файлов. GRPCClientAuth требует от клиентов gRPC
+// сертификат, подписанный CA из CAFile (mTLS для вызовов между сервисами).
+type TLSConf struct {
+	Enabled        bool          `yaml:"enabled"`
+	CertFile       string        `yaml:"cert_file"`
+	KeyFile        string        `yaml:"key_file"`
+	CAFile         string        `yaml:"ca_file"`
+	GRPCClientAuth bool          `yaml:"grpc_client_auth"`
+	ReloadInterval time.Duration `yaml:"reload_interval"`
 }
 
 type StorageConf struct {
-	Type string `toml:"type"` // "memory" или "sql"
+	Type  string    `yaml:"type"` // "memory" или "sql"
+	Cache CacheConf `yaml:"cache"`
 }
 
-type DBConf struct {
-	DSN string `toml:"dsn"`
+// CacheConf - кэш события по id и выборок за день, неделю и месяц.
+type CacheConf struct {
+	Enabled  bool          `yaml:"enabled"`
+	TTL      time.Duration `yaml:"ttl"`
+	Capacity int           `yaml:"capacity"` // число событий и выборок вместе
 }
 
-func NewConfigFromFile(path string) (Config, error) {
-	if path == "" {
-		return Config{}, fmt.Errorf("empty config path")
-	}
+type TracingConf struct {
+	Exporter    string  `yaml:"exporter"` // "none", "stdout" или "otlp"
+	Endpoint    string  `yaml:"endpoint"` // адрес OTLP коллектора
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
 
-	f, err := os.ReadFile(path)
-	if err != nil {
-		return Config{}, err
-	}
+// envPrefix - префикс переменных окружения: CALENDAR_SERVER_HTTP_PORT, CALENDAR_DB_DSN, ...
+const envPrefix = "CALENDAR"
 
-	var cfg Config
-	if err := yaml.Unmarshal(f, &cfg); err != nil {
-		return Config{}, err
+// NewConfig собирает конфигурацию слоями: файл (YAML или TOML по расширению),
+// переменные окружения CALENDAR_* и переопределения -set из командной строки;
+// каждый следующий слой перекрывает предыдущий и значения по умолчанию (defaultConfig),
+// в том числе явно заданными нулями. Пустой path - конфигурация без файла.
+func NewConfig(path string, overrides config.Overrides) (Config, error) {
+	cfg := defaultConfig()
+	if path != "" {
+		if err := config.LoadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
 	}
-
-	// defaults
-	if cfg.Logger.Level == "" {
-		cfg.Logger.Level = "info"
+	if err := config.ApplyEnv(envPrefix, &cfg); err != nil {
+		return Config{}, err
 	}
-	if cfg.Server.Host == "" {
-		cfg.Server.Host = "0.0.0.0"
+	if err := overrides.Apply(&cfg); err != nil {
+		return Config{}, err
 	}
-	// Обратная совместимость: если указан старый port, используем его для HTTP
-	if cfg.Server.Port != 0 && cfg.Server.HTTPPort == 0 {
+	// Обратная совместимость: если указан старый port, используем его для HTTP;
+	// поэтому http_port получает значение по умолчанию только здесь
+	if cfg.Server.HTTPPort == 0 {
 		cfg.Server.HTTPPort = cfg.Server.Port
 	}
 	if cfg.Server.HTTPPort == 0 {
 		cfg.Server.HTTPPort = 8080
 	}
-	if cfg.Server.GRPCPort == 0 {
-		cfg.Server.GRPCPort = 50051
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
 	}
-	if cfg.Storage.Type == "" {
-		cfg.Storage.Type = "memory"
+	return cfg, nil
+}
+
+// defaultConfig - значения по умолчанию, поверх которых применяются файл,
+// окружение и -set.
+func defaultConfig() Config {
+	return Config{
+		Logger: cmdutil.LoggerConf{Level: "info"},
+		Server: ServerConf{
+			Host:     "0.0.0.0",
+			GRPCPort: 50051,
+		},
+		Storage: StorageConf{
+			Type:  "memory",
+			Cache: CacheConf{TTL: time.Minute, Capacity: 1000},
+		},
+		DB:    cmdutil.DefaultDBConf(),
+		Admin: AdminConf{Host: "127.0.0.1"},
+		Tracing: TracingConf{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: "calendar",
+		},
+	}
+}
+
+// Validate проверяет итоговую конфигурацию и возвращает все найденные ошибки сразу.
+func (cfg Config) Validate() error {
+	var errs []error
+	if err := cmdutil.LoggerConfig(cfg.Logger).Validate(); err != nil {
+		errs = append(errs, config.Prefix("logger", err))
 	}
 
-	return cfg, nil
+	if err := checkPort(cfg.Server.HTTPPort); err != nil {
+		errs = append(errs, fmt.Errorf("server.http_port: %w", err))
+	}
+	if err := checkPort(cfg.Server.GRPCPort); err != nil {
+		errs = append(errs, fmt.Errorf("server.grpc_port: %w", err))
+	}
+	if cfg.Server.HTTPPort == cfg.Server.GRPCPort {
+		errs = append(errs, fmt.Errorf("server.grpc_port: must differ from http_port %d", cfg.Server.HTTPPort))
+	}
+	if cfg.Server.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown_delay: must not be negative"))
+	}
+
+	if c := cfg.Storage.Cache; c.Enabled && (c.TTL <= 0 || c.Capacity <= 0) {
+		errs = append(errs, fmt.Errorf("storage.cache: ttl and capacity must be positive"))
+	}
+
+	if t := cfg.Server.TLS; t.Enabled {
+		if t.CertFile == "" || t.KeyFile == "" {
+			errs = append(errs, fmt.Errorf("server.tls: cert_file and key_file are required"))
+		}
+		if t.GRPCClientAuth && t.CAFile == "" {
+			errs = append(errs, fmt.Errorf("server.tls.ca_file: required for grpc_client_auth"))
+		}
+		if t.ReloadInterval < 0 {
+			errs = append(errs, fmt.Errorf("server.tls.reload_interval: must not be negative"))
+		}
+	}
+
+	if cfg.Server.MaxBodySize < 0 {
+		errs = append(errs, fmt.Errorf("server.max_body_size: must not be negative"))
+	}
+	if cfg.Server.Gzip.MinSize < 0 {
+		errs = append(errs, fmt.Errorf("server.gzip.min_size: must not be negative"))
+	}
+
+	if cfg.CORS.Enabled && len(cfg.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, fmt.Errorf("cors: enabled, but no allowed_origins set"))
+	}
+	for i, o := range cfg.CORS.AllowedOrigins {
+		if o == "*" {
+			if cfg.CORS.AllowCredentials {
+				// любой сайт мог бы вызывать API с cookie и авторизацией пользователя
+				errs = append(errs, fmt.Errorf("cors.allowed_origins: \"*\" can not be used with allow_credentials"))
+			}
+			continue
+		}
+		if !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			errs = append(errs, fmt.Errorf("cors.allowed_origins[%d]: %q must start with http:// or https://", i, o))
+		}
+	}
+	if cfg.CORS.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("cors.max_age: must not be negative"))
+	}
+
+	switch cfg.Storage.Type {
+	case "memory":
+	case "sql":
+		if err := cfg.DB.Validate(); err != nil {
+			errs = append(errs, config.Prefix("db", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.type: unknown type %q, expected memory or sql", cfg.Storage.Type))
+	}
+
+	if cfg.Auth.Enabled && cfg.Auth.JWT.HS256Secret == "" && cfg.Auth.JWT.JWKSFile == "" && len(cfg.Auth.APIKeys) == 0 {
+		errs = append(errs, fmt.Errorf("auth: enabled, but no jwt.hs256_secret, jwt.jwks_file or api_keys set"))
+	}
+	if cfg.Auth.JWT.Leeway < 0 {
+		errs = append(errs, fmt.Errorf("auth.jwt.leeway: must not be negative"))
+	}
+
+	if _, err := internalhttp.ParseTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("server.trusted_proxies: %w", err))
+	}
+
+	if cfg.RateLimit.AuthFailures.Rate < 0 || cfg.RateLimit.AuthFailures.Burst < 0 {
+		errs = append(errs, fmt.Errorf("rate_limit.auth_failures: rate and burst must not be negative"))
+	}
+	if cfg.RateLimit.Rate < 0 || cfg.RateLimit.Burst < 0 {
+		errs = append(errs, fmt.Errorf("rate_limit: rate and burst must not be negative"))
+	}
+	for route, r := range cfg.RateLimit.Routes {
+		if r.Rate < 0 || r.Burst < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.routes.%s: rate and burst must not be negative", route))
+		}
+	}
+
+	switch strings.ToLower(cfg.Tracing.Exporter) {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: unknown exporter %q", cfg.Tracing.Exporter))
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio: must be between 0 and 1"))
+	}
+
+	if cfg.Reload.WatchInterval < 0 {
+		errs = append(errs, fmt.Errorf("reload.watch_interval: must not be negative"))
+	}
+	if cfg.Admin.Port != 0 {
+		if err := checkPort(cfg.Admin.Port); err != nil {
+			errs = append(errs, fmt.Errorf("admin.port: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("must be between 1 and 65535, got %d", port)
+	}
+	return nil
 }
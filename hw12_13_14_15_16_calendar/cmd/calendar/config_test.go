@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+)
+
+func TestNewConfigKeepsExplicitZeros(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := "server:\n  port: 9000\ndb:\n  read_retries: 0\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := NewConfig(path, config.Overrides{"db.connect_backoff=0s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DB.ReadRetries != 0 || cfg.DB.ConnectBackoff != 0 {
+		t.Fatalf("expected explicit zeros to be kept, got read_retries=%d connect_backoff=%v",
+			cfg.DB.ReadRetries, cfg.DB.ConnectBackoff)
+	}
+	// незаданные значения берутся из умолчаний
+	if cfg.DB.ConnectAttempts != 10 || cfg.Storage.Cache.TTL != time.Minute || cfg.Server.GRPCPort != 50051 {
+		t.Fatalf("expected defaults for unset values, got %+v", cfg)
+	}
+	if cfg.Server.HTTPPort != 9000 {
+		t.Fatalf("expected legacy server.port to be used for http, got %d", cfg.Server.HTTPPort)
+	}
+}
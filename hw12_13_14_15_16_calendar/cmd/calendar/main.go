@@ -3,23 +3,40 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/app"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cache"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/health"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/ratelimit"
+	adminserver "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/admin"
 	internalgrpc "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/grpc"
 	internalhttp "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/http"
 	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
 	sqlstorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/sql"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tlsconfig"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
 )
 
-var configFile string
+var (
+	configFile string
+	overrides  config.Overrides
+)
 
 func init() {
-	flag.StringVar(&configFile, "config", "/etc/calendar/config.toml", "Path to configuration file")
+	flag.StringVar(&configFile, "config", "/etc/calendar/config.toml",
+		"Path to configuration file (.yaml or .toml), empty - only env and flags")
+	flag.Var(&overrides, "set", "Override config value, e.g. -set server.http_port=8081 (repeatable)")
 }
 
 func main() {
@@ -30,39 +47,185 @@ func main() {
 		return
 	}
 
-	cfg, err := NewConfigFromFile(configFile)
+	cfg, err := NewConfig(configFile, overrides)
 	if err != nil {
-		panic("failed to load config: " + err.Error())
+		fmt.Fprintln(os.Stderr, "failed to load config: "+err.Error())
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "config" {
+		if err := configCommand(cfg, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
 	}
 
-	logg := logger.New(cfg.Logger.Level)
+	// SIGHUP принимается с самого начала: иначе сигнал, пришедший во время подключения
+	// к базе или очереди, завершил бы процесс; reloader обработает его после запуска
+	hup := config.NotifyHangup()
+
+	logg, err := logger.Open(cmdutil.LoggerConfig(cfg.Logger))
+	if err != nil {
+		panic("failed to open logger: " + err.Error())
+	}
+	defer logg.Close()
+	// сторонние библиотеки, пишущие через log/slog, попадают в тот же лог
+	slog.SetDefault(logg.Slog())
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		logg.Error("failed to setup tracing: " + err.Error())
+		os.Exit(1)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logg.Error("failed to flush traces: " + err.Error())
+		}
+	}()
+
+	checker := health.New(logg.Component("health"), 0)
 
 	var storage app.Storage
 	switch cfg.Storage.Type {
 	case "sql":
-		sql := sqlstorage.New(cfg.DB.DSN)
+		sql := sqlstorage.New(cfg.DB.DSN, cmdutil.StorageOptions(cfg.DB, logg.Component("storage"))...)
 		if err := sql.Connect(context.Background()); err != nil {
 			logg.Error("failed to connect to db: " + err.Error())
-			os.Exit(1)
+			os.Exit(1) //nolint:gocritic
+		}
+		if cfg.DB.AutoMigrate {
+			if err := sql.Migrate(context.Background()); err != nil {
+				logg.Error("failed to migrate db: " + err.Error())
+				os.Exit(1) //nolint:gocritic
+			}
+			logg.Info("db migrations applied")
 		}
 
+		checker.Add("storage", sql.Ping)
 		storage = sql
 	default:
 		storage = memorystorage.New()
 	}
 
-	calendar := app.New(logg, storage)
+	storage = metrics.InstrumentStorage(storage)
+	if c := cfg.Storage.Cache; c.Enabled {
+		storage = cache.New(storage, cache.WithTTL(c.TTL), cache.WithCapacity(c.Capacity))
+	}
+	calendar := app.New(logg.Component("app"), storage)
 
-	httpServer := internalhttp.NewServer(logg, calendar, cfg.Server.Host, cfg.Server.HTTPPort)
-	grpcServer := internalgrpc.NewServer(logg, calendar, cfg.Server.Host, cfg.Server.GRPCPort)
+	// ограничитель создаётся всегда, чтобы его можно было включить перезагрузкой конфигурации
+	limiter := ratelimit.New(rateLimitConfig(cfg.RateLimit))
+	// CORS тоже создаётся всегда и включается перезагрузкой
+	cors := internalhttp.NewCORS(corsConfig(cfg.CORS))
+	httpOpts := []internalhttp.Option{
+		internalhttp.WithRateLimit(limiter),
+		internalhttp.WithCORS(cors),
+		internalhttp.WithMaxBodySize(cfg.Server.MaxBodySize),
+	}
+	// адреса уже проверены при загрузке конфигурации
+	proxies, _ := internalhttp.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	httpOpts = append(httpOpts, internalhttp.WithTrustedProxies(proxies))
+	if cfg.Server.Gzip.Enabled {
+		httpOpts = append(httpOpts, internalhttp.WithGzip(cfg.Server.Gzip.MinSize))
+	}
+	grpcOpts := []internalgrpc.Option{internalgrpc.WithRateLimit(limiter)}
+	if cfg.Auth.Enabled {
+		authenticator, err := auth.New(authConfig(cfg.Auth))
+		if err != nil {
+			logg.Error("failed to configure auth: " + err.Error())
+			os.Exit(1) //nolint:gocritic
+		}
+		httpOpts = append(httpOpts, internalhttp.WithAuth(authenticator))
+		grpcOpts = append(grpcOpts, internalgrpc.WithAuth(authenticator))
+	}
+	var certs *tlsconfig.Reloader
+	if t := cfg.Server.TLS; t.Enabled {
+		certs, err = tlsconfig.New(logg.Component("tls"), tlsconfig.Config{
+			CertFile: t.CertFile,
+			KeyFile:  t.KeyFile,
+			CAFile:   t.CAFile,
+		})
+		if err != nil {
+			logg.Error("failed to load tls certificates: " + err.Error())
+			os.Exit(1) //nolint:gocritic
+		}
+		// без проверки клиентских сертификатов ошибки не бывает
+		httpTLS, _ := certs.ServerConfig(false)
+		grpcTLS, err := certs.ServerConfig(t.GRPCClientAuth)
+		if err != nil {
+			logg.Error("failed to configure grpc tls: " + err.Error())
+			os.Exit(1) //nolint:gocritic
+		}
+		httpOpts = append(httpOpts, internalhttp.WithTLS(httpTLS))
+		grpcOpts = append(grpcOpts, internalgrpc.WithTLS(grpcTLS))
+	}
+
+	httpServer := internalhttp.NewServer(logg.Component("http"), calendar, cfg.Server.Host, cfg.Server.HTTPPort, httpOpts...)
+	httpServer.Handle("/healthz", checker.LiveHandler())
+	httpServer.Handle("/readyz", checker.ReadyHandler())
+	grpcServer := internalgrpc.NewServer(logg.Component("grpc"), calendar, cfg.Server.Host, cfg.Server.GRPCPort, grpcOpts...)
+	grpcServer.RegisterHealth(checker.GRPC())
 
+	// SIGHUP не завершает процесс, а перечитывает конфигурацию (см. hup и reloader)
 	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	// серверы живут дольше ctx: по сигналу сначала снимается готовность,
+	// и только после shutdown_delay они перестают принимать запросы
+	serveCtx, stopServing := context.WithCancel(context.Background())
+	defer stopServing()
+
+	// SIGHUP и изменение файла конфигурации перечитывают настройки без перезапуска
+	reloader := config.NewReloader(logg.Component("config"), cfg, func() (Config, error) {
+		return NewConfig(configFile, overrides)
+	})
+	reloader.OnReload(func(cfg Config) error {
+		return logg.SetLevels(cfg.Logger.Level, cfg.Logger.Components)
+	}, "logger.level", "logger.components")
+	reloader.OnReload(func(cfg Config) error {
+		limiter.Configure(rateLimitConfig(cfg.RateLimit))
+		return nil
+	}, "rate_limit")
+	reloader.OnReload(func(cfg Config) error {
+		cors.Configure(corsConfig(cfg.CORS))
+		return nil
+	}, "cors")
+	go reloader.Run(ctx, hup, configFile, cfg.Reload.WatchInterval)
+	go checker.Watch(ctx, 5*time.Second)
+	if certs != nil {
+		go certs.Watch(ctx, cfg.Server.TLS.ReloadInterval)
+	}
+
+	if cfg.Admin.Port != 0 {
+		admin := adminserver.NewServer(logg.Component("admin"), cfg.Admin.Host, cfg.Admin.Port)
+		admin.Handle("/metrics", metrics.Handler())
+		admin.RegisterLogLevel(logg)
+		go func() {
+			if err := admin.Start(serveCtx); err != nil {
+				logg.Error("admin server error: " + err.Error())
+			}
+		}()
+	}
+
 	go func() {
 		<-ctx.Done()
 
+		checker.Drain()
+		if cfg.Server.ShutdownDelay > 0 {
+			logg.Info(fmt.Sprintf("waiting %s before shutdown", cfg.Server.ShutdownDelay))
+			time.Sleep(cfg.Server.ShutdownDelay)
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 		defer cancel()
 
@@ -72,22 +235,68 @@ func main() {
 		if err := grpcServer.Stop(ctx); err != nil {
 			logg.Error("failed to stop grpc server: " + err.Error())
 		}
+		stopServing()
 	}()
 
 	logg.Info("calendar is running...")
 
 	// Запускаем оба сервера в отдельных горутинах
 	go func() {
-		if err := grpcServer.Start(ctx); err != nil {
+		if err := grpcServer.Start(serveCtx); err != nil {
 			logg.Error("failed to start grpc server: " + err.Error())
 			cancel()
 		}
 	}()
 
 	// HTTP сервер запускаем в основной горутине
-	if err := httpServer.Start(ctx); err != nil {
+	if err := httpServer.Start(serveCtx); err != nil {
 		logg.Error("failed to start http server: " + err.Error())
 		cancel()
 		os.Exit(1) //nolint:gocritic
 	}
 }
+
+// configCommand обслуживает подкоманды "calendar config ...".
+func configCommand(cfg Config, args []string) error {
+	if len(args) == 1 && args[0] == "print" {
+		// итоговая конфигурация после всех слоёв, секреты скрыты
+		return config.Print(os.Stdout, cfg)
+	}
+	return fmt.Errorf("usage: calendar [-config FILE] [-set key=value ...] config print")
+}
+
+func rateLimitConfig(c RateLimitConf) ratelimit.Config {
+	routes := make(map[string]ratelimit.Rule, len(c.Routes))
+	for route, r := range c.Routes {
+		routes[route] = ratelimit.Rule{Rate: r.Rate, Burst: r.Burst}
+	}
+	return ratelimit.Config{
+		Enabled:      c.Enabled,
+		Default:      ratelimit.Rule{Rate: c.Rate, Burst: c.Burst},
+		Routes:       routes,
+		AuthFailures: ratelimit.Rule{Rate: c.AuthFailures.Rate, Burst: c.AuthFailures.Burst},
+	}
+}
+
+func corsConfig(c CORSConf) internalhttp.CORSConfig {
+	return internalhttp.CORSConfig{
+		Enabled:          c.Enabled,
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           int(c.MaxAge.Seconds()),
+	}
+}
+
+func authConfig(c AuthConf) auth.Config {
+	return auth.Config{
+		HS256Secret: c.JWT.HS256Secret,
+		JWKSFile:    c.JWT.JWKSFile,
+		Issuer:      c.JWT.Issuer,
+		Audience:    c.JWT.Audience,
+		Leeway:      c.JWT.Leeway,
+		APIKeys:     c.APIKeys,
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+)
+
+type Config struct {
+	Logger    cmdutil.LoggerConf `yaml:"logger"`
+	Storage   StorageConf        `yaml:"storage"`
+	DB        cmdutil.DBConf     `yaml:"db"`
+	Queue     QueueConf          `yaml:"queue"`
+	Scheduler SchedulerConf      `yaml:"scheduler"`
+	Outbox    OutboxConf         `yaml:"outbox"`
+	Admin     AdminConf          `yaml:"admin"`
+	Reload    ReloadConf         `yaml:"reload"`
+}
+
+// ReloadConf - перечитывание конфигурации без перезапуска. По SIGHUP конфигурация
+// перечитывается всегда, при WatchInterval > 0 файл ещё и проверяется на изменения.
+type ReloadConf struct {
+	WatchInterval time.Duration `yaml:"watch_interval"`
+}
+
+type StorageConf struct {
+	Type string `yaml:"type"` // "memory" или "sql"
+}
+
+type QueueConf struct {
+	URL            string        `yaml:"url" secret:"true"`
+	Exchange       string        `yaml:"exchange"`
+	Queue          string        `yaml:"queue"`
+	RoutingKey     string        `yaml:"routing_key"`
+	ReconnectDelay time.Duration `yaml:"reconnect_delay"`
+}
+
+type SchedulerConf struct {
+	Interval time.Duration `yaml:"interval"`
+	// ClaimLease - через сколько напоминание, отмеченное поставленным в очередь, но не
+	// отправленное, ставится снова (планировщик мог упасть до публикации)
+	ClaimLease time.Duration `yaml:"claim_lease"`
+}
+
+// OutboxConf - публикация изменений событий из outbox (только для sql хранилища).
+type OutboxConf struct {
+	Enabled    bool          `yaml:"enabled"`
+	Queue      string        `yaml:"queue"`
+	RoutingKey string        `yaml:"routing_key"`
+	Interval   time.Duration `yaml:"interval"`
+	BatchSize  int           `yaml:"batch_size"`
+	// Retention - сколько хранить доставленные записи перед удалением
+	Retention time.Duration `yaml:"retention"`
+}
+
+// AdminConf - служебный HTTP сервер (/metrics); при нулевом порте не запускается.
+type AdminConf struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// envPrefix - префикс переменных окружения: CALENDAR_SCHEDULER_QUEUE_URL, CALENDAR_SCHEDULER_DB_DSN, ...
+const envPrefix = "CALENDAR_SCHEDULER"
+
+// NewConfig собирает конфигурацию слоями: файл (YAML или TOML по расширению),
+// переменные окружения CALENDAR_SCHEDULER_* и переопределения -set из командной строки;
+// каждый следующий слой перекрывает предыдущий и значения по умолчанию (defaultConfig),
+// в том числе явно заданными нулями. Пустой path - конфигурация без файла.
+func NewConfig(path string, overrides config.Overrides) (Config, error) {
+	cfg := defaultConfig()
+	if path != "" {
+		if err := config.LoadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := config.ApplyEnv(envPrefix, &cfg); err != nil {
+		return Config{}, err
+	}
+	if err := overrides.Apply(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// defaultConfig - значения по умолчанию, поверх которых применяются файл,
+// окружение и -set.
+func defaultConfig() Config {
+	return Config{
+		Logger:  cmdutil.LoggerConf{Level: "info"},
+		Storage: StorageConf{Type: "memory"},
+		DB:      cmdutil.DefaultDBConf(),
+		Queue: QueueConf{
+			Exchange: "calendar",
+			Queue:    "notifications",
+		},
+		Scheduler: SchedulerConf{
+			Interval:   time.Minute,
+			ClaimLease: 5 * time.Minute,
+		},
+		Outbox: OutboxConf{
+			Queue:     "event_changes",
+			Interval:  time.Second,
+			Retention: 24 * time.Hour,
+		},
+		Admin: AdminConf{Host: "127.0.0.1"},
+	}
+}
+
+// Validate проверяет итоговую конфигурацию и возвращает все найденные ошибки сразу.
+func (cfg Config) Validate() error {
+	var errs []error
+	if err := cmdutil.LoggerConfig(cfg.Logger).Validate(); err != nil {
+		errs = append(errs, config.Prefix("logger", err))
+	}
+
+	switch cfg.Storage.Type {
+	case "memory":
+	case "sql":
+		if err := cfg.DB.Validate(); err != nil {
+			errs = append(errs, config.Prefix("db", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.type: unknown type %q, expected memory or sql", cfg.Storage.Type))
+	}
+
+	if cfg.Queue.URL == "" {
+		errs = append(errs, fmt.Errorf("queue.url: required"))
+	}
+	if cfg.Scheduler.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.interval: must be positive"))
+	}
+	if cfg.Scheduler.ClaimLease <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.claim_lease: must be positive"))
+	}
+	if cfg.Outbox.Enabled && cfg.Outbox.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("outbox.interval: must be positive"))
+	}
+	if cfg.Outbox.Enabled && cfg.Outbox.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("outbox.retention: must be positive"))
+	}
+
+	if cfg.Reload.WatchInterval < 0 {
+		errs = append(errs, fmt.Errorf("reload.watch_interval: must not be negative"))
+	}
+	if cfg.Admin.Port < 0 || cfg.Admin.Port > 65535 {
+		errs = append(errs, fmt.Errorf("admin.port: must be between 0 and 65535, got %d", cfg.Admin.Port))
+	}
+	return errors.Join(errs...)
+}
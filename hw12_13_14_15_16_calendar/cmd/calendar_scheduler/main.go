@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/health"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/outbox"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	amqpqueue "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue/amqp"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/scheduler"
+	adminserver "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/admin"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+	sqlstorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/sql"
+)
+
+var (
+	configFile string
+	overrides  config.Overrides
+)
+
+func init() {
+	flag.StringVar(&configFile, "config", "/etc/calendar/scheduler_config.yaml",
+		"Path to configuration file (.yaml or .toml), empty - only env and flags")
+	flag.Var(&overrides, "set", "Override config value, e.g. -set queue.prefetch=10 (repeatable)")
+}
+
+func main() {
+	flag.Parse()
+
+	cfg, err := NewConfig(configFile, overrides)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config: "+err.Error())
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "config" {
+		if err := configCommand(cfg, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	// SIGHUP принимается с самого начала: иначе сигнал, пришедший во время подключения
+	// к базе или очереди, завершил бы процесс; reloader обработает его после запуска
+	hup := config.NotifyHangup()
+
+	logg, err := logger.Open(cmdutil.LoggerConfig(cfg.Logger))
+	if err != nil {
+		panic("failed to open logger: " + err.Error())
+	}
+	defer logg.Close()
+	// сторонние библиотеки, пишущие через log/slog, попадают в тот же лог
+	slog.SetDefault(logg.Slog())
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	checker := health.New(logg.Component("health"), 0)
+
+	var store scheduler.Storage
+	var outboxStore outbox.Storage
+	switch cfg.Storage.Type {
+	case "sql":
+		sql := sqlstorage.New(cfg.DB.DSN, cmdutil.StorageOptions(cfg.DB, logg.Component("storage"))...)
+		if err := sql.Connect(ctx); err != nil {
+			logg.Error("failed to connect to db: " + err.Error())
+			os.Exit(1)
+		}
+		defer sql.Close(context.Background())
+		checker.Add("storage", sql.Ping)
+		store = sql
+		outboxStore = sql
+	default:
+		store = memorystorage.New()
+	}
+
+	// SIGHUP и изменение файла конфигурации перечитывают настройки без перезапуска
+	reloader := config.NewReloader(logg.Component("config"), cfg, func() (Config, error) {
+		return NewConfig(configFile, overrides)
+	})
+	reloader.OnReload(func(cfg Config) error {
+		return logg.SetLevels(cfg.Logger.Level, cfg.Logger.Components)
+	}, "logger.level", "logger.components")
+	go reloader.Run(ctx, hup, configFile, cfg.Reload.WatchInterval)
+
+	client := amqpqueue.New(logg.Component("queue"), amqpqueue.Config{
+		URL:            cfg.Queue.URL,
+		Exchange:       cfg.Queue.Exchange,
+		Queue:          cfg.Queue.Queue,
+		RoutingKey:     cfg.Queue.RoutingKey,
+		ReconnectDelay: cfg.Queue.ReconnectDelay,
+	})
+	defer client.Close()
+	checker.Add("queue", client.Ping)
+
+	// при запуске сразу создаём exchange и очередь
+	if err := client.Connect(ctx); err != nil {
+		logg.Error("failed to connect to queue: " + err.Error())
+		os.Exit(1) //nolint:gocritic
+	}
+
+	if cfg.Admin.Port != 0 {
+		admin := adminserver.NewServer(logg, cfg.Admin.Host, cfg.Admin.Port)
+		admin.Handle("/metrics", metrics.Handler())
+		admin.RegisterLogLevel(logg)
+		admin.Handle("/healthz", checker.LiveHandler())
+		admin.Handle("/readyz", checker.ReadyHandler())
+		go func() {
+			if err := admin.Start(ctx); err != nil {
+				logg.Error("admin server error: " + err.Error())
+			}
+		}()
+	}
+
+	if cfg.Outbox.Enabled {
+		if outboxStore == nil {
+			logg.Error("outbox relay requires sql storage, relay is disabled")
+		} else {
+			changes := amqpqueue.New(logg.Component("queue"), amqpqueue.Config{
+				URL:            cfg.Queue.URL,
+				Exchange:       cfg.Queue.Exchange,
+				Queue:          cfg.Outbox.Queue,
+				RoutingKey:     cfg.Outbox.RoutingKey,
+				ReconnectDelay: cfg.Queue.ReconnectDelay,
+			})
+			defer changes.Close()
+			checker.Add("outbox_queue", changes.Ping)
+
+			relay := outbox.NewRelay(logg.Component("outbox"), outboxStore, changes, cfg.Outbox.Interval, cfg.Outbox.BatchSize,
+				outbox.WithRetention(cfg.Outbox.Retention))
+			go relay.Run(ctx) //nolint:errcheck
+		}
+	}
+
+	sched := scheduler.New(logg.Component("scheduler"), store, queue.NewNotificationPublisher(client), cfg.Scheduler.Interval,
+		scheduler.WithClaimLease(cfg.Scheduler.ClaimLease))
+
+	logg.Info("calendar scheduler is running...")
+	if err := sched.Run(ctx); err != nil {
+		logg.Error("scheduler stopped: " + err.Error())
+	}
+}
+
+// configCommand обслуживает подкоманды "calendar_scheduler config ...".
+func configCommand(cfg Config, args []string) error {
+	if len(args) == 1 && args[0] == "print" {
+		// итоговая конфигурация после всех слоёв, секреты скрыты
+		return config.Print(os.Stdout, cfg)
+	}
+	return fmt.Errorf("usage: calendar_scheduler [-config FILE] [-set key=value ...] config print")
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+)
+
+type Config struct {
+	Logger  cmdutil.LoggerConf `yaml:"logger"`
+	Storage StorageConf        `yaml:"storage"`
+	DB      cmdutil.DBConf     `yaml:"db"`
+	Queue   QueueConf          `yaml:"queue"`
+	Admin   AdminConf          `yaml:"admin"`
+	Reload  ReloadConf         `yaml:"reload"`
+	Webhook WebhookConf        `yaml:"webhook"`
+}
+
+// ReloadConf - перечитывание конфигурации без перезапуска. По SIGHUP конфигурация
+// перечитывается всегда, при WatchInterval > 0 файл ещё и проверяется на изменения.
+type ReloadConf struct {
+	WatchInterval time.Duration `yaml:"watch_interval"`
+}
+
+type StorageConf struct {
+	Type string `yaml:"type"` // "memory" или "sql"
+}
+
+type QueueConf struct {
+	URL            string        `yaml:"url" secret:"true"`
+	Exchange       string        `yaml:"exchange"`
+	Queue          string        `yaml:"queue"`
+	RoutingKey     string        `yaml:"routing_key"`
+	Prefetch       int           `yaml:"prefetch"`
+	ReconnectDelay time.Duration `yaml:"reconnect_delay"`
+	Retry          RetryConf     `yaml:"retry"`
+}
+
+// RetryConf - политика повторных попыток доставки; незаполненные поля берутся из queue.DefaultRetryPolicy.
+type RetryConf struct {
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Multiplier     float64       `yaml:"multiplier"`
+}
+
+// WebhookConf - доставка напоминаний на webhook пользователей.
+type WebhookConf struct {
+	Secret      string                         `yaml:"secret" secret:"true"`
+	Timeout     time.Duration                  `yaml:"timeout"`
+	MaxAttempts int                            `yaml:"max_attempts"`
+	Backoff     time.Duration                  `yaml:"backoff"`
+	Endpoints   map[string]WebhookEndpointConf `yaml:"endpoints"` // по user_id
+}
+
+type WebhookEndpointConf struct {
+	URL    string `yaml:"url"`
+	Secret string `yaml:"secret" secret:"true"`
+}
+
+// AdminConf - служебный HTTP сервер; при нулевом порте не запускается.
+type AdminConf struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+// envPrefix - префикс переменных окружения: CALENDAR_SENDER_QUEUE_URL, CALENDAR_SENDER_DB_DSN, ...
+const envPrefix = "CALENDAR_SENDER"
+
+// NewConfig собирает конфигурацию слоями: файл (YAML или TOML по расширению),
+// переменные окружения CALENDAR_SENDER_* и переопределения -set из командной строки;
+// каждый следующий слой перекрывает предыдущий и значения по умолчанию (defaultConfig),
+// в том числе явно заданными нулями. Пустой path - конфигурация без файла.
+func NewConfig(path string, overrides config.Overrides) (Config, error) {
+	cfg := defaultConfig()
+	if path != "" {
+		if err := config.LoadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := config.ApplyEnv(envPrefix, &cfg); err != nil {
+		return Config{}, err
+	}
+	if err := overrides.Apply(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// defaultConfig - значения по умолчанию, поверх которых применяются файл,
+// окружение и -set.
+func defaultConfig() Config {
+	return Config{
+		Logger:  cmdutil.LoggerConf{Level: "info"},
+		Storage: StorageConf{Type: "memory"},
+		DB:      cmdutil.DefaultDBConf(),
+		Queue: QueueConf{
+			Exchange: "calendar",
+			Queue:    "notifications",
+		},
+		Admin: AdminConf{Host: "127.0.0.1"},
+	}
+}
+
+// Validate проверяет итоговую конфигурацию и возвращает все найденные ошибки сразу.
+func (cfg Config) Validate() error {
+	var errs []error
+	if err := cmdutil.LoggerConfig(cfg.Logger).Validate(); err != nil {
+		errs = append(errs, config.Prefix("logger", err))
+	}
+
+	switch cfg.Storage.Type {
+	case "memory":
+	case "sql":
+		if err := cfg.DB.Validate(); err != nil {
+			errs = append(errs, config.Prefix("db", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.type: unknown type %q, expected memory or sql", cfg.Storage.Type))
+	}
+
+	if cfg.Queue.URL == "" {
+		errs = append(errs, fmt.Errorf("queue.url: required"))
+	}
+	if cfg.Queue.Retry.Multiplier < 0 {
+		errs = append(errs, fmt.Errorf("queue.retry.multiplier: must not be negative"))
+	}
+	for userID, e := range cfg.Webhook.Endpoints {
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			errs = append(errs, fmt.Errorf("webhook.endpoints.%s.url: expected http(s) URL, got %q", userID, e.URL))
+		}
+		// пустой ключ HMAC делает подпись бессмысленной
+		if e.Secret == "" && cfg.Webhook.Secret == "" {
+			errs = append(errs, fmt.Errorf("webhook.endpoints.%s.secret: required when webhook.secret is empty", userID))
+		}
+	}
+
+	if cfg.Reload.WatchInterval < 0 {
+		errs = append(errs, fmt.Errorf("reload.watch_interval: must not be negative"))
+	}
+	if cfg.Admin.Port < 0 || cfg.Admin.Port > 65535 {
+		errs = append(errs, fmt.Errorf("admin.port: must be between 0 and 65535, got %d", cfg.Admin.Port))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/cmdutil"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/health"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	amqpqueue "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue/amqp"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/sender"
+	adminserver "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/server/admin"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+	sqlstorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/sql"
+)
+
+var (
+	configFile string
+	overrides  config.Overrides
+)
+
+func init() {
+	flag.StringVar(&configFile, "config", "/etc/calendar/sender_config.yaml",
+		"Path to configuration file (.yaml or .toml), empty - only env and flags")
+	flag.Var(&overrides, "set", "Override config value, e.g. -set queue.prefetch=10 (repeatable)")
+}
+
+func main() {
+	flag.Parse()
+
+	cfg, err := NewConfig(configFile, overrides)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config: "+err.Error())
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "config" {
+		if err := configCommand(cfg, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	// SIGHUP принимается с самого начала: иначе сигнал, пришедший во время подключения
+	// к базе или очереди, завершил бы процесс; reloader обработает его после запуска
+	hup := config.NotifyHangup()
+
+	logg, err := logger.Open(cmdutil.LoggerConfig(cfg.Logger))
+	if err != nil {
+		panic("failed to open logger: " + err.Error())
+	}
+	defer logg.Close()
+	// сторонние библиотеки, пишущие через log/slog, попадают в тот же лог
+	slog.SetDefault(logg.Slog())
+
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	checker := health.New(logg.Component("health"), 0)
+
+	var store sender.Storage
+	switch cfg.Storage.Type {
+	case "sql":
+		sql := sqlstorage.New(cfg.DB.DSN, cmdutil.StorageOptions(cfg.DB, logg.Component("storage"))...)
+		if err := sql.Connect(ctx); err != nil {
+			logg.Error("failed to connect to db: " + err.Error())
+			os.Exit(1)
+		}
+		defer sql.Close(context.Background())
+		checker.Add("storage", sql.Ping)
+		store = sql
+	default:
+		store = memorystorage.New()
+	}
+
+	retry := queue.RetryPolicy{
+		MaxAttempts:    cfg.Queue.Retry.MaxAttempts,
+		InitialBackoff: cfg.Queue.Retry.InitialBackoff,
+		MaxBackoff:     cfg.Queue.Retry.MaxBackoff,
+		Multiplier:     cfg.Queue.Retry.Multiplier,
+	}
+	client := amqpqueue.New(logg.Component("queue"), amqpqueue.Config{
+		URL:            cfg.Queue.URL,
+		Exchange:       cfg.Queue.Exchange,
+		Queue:          cfg.Queue.Queue,
+		RoutingKey:     cfg.Queue.RoutingKey,
+		Prefetch:       cfg.Queue.Prefetch,
+		ReconnectDelay: cfg.Queue.ReconnectDelay,
+		Retry:          retry,
+	})
+	defer client.Close()
+	checker.Add("queue", client.Ping)
+
+	if cfg.Admin.Port != 0 {
+		admin := adminserver.NewServer(logg, cfg.Admin.Host, cfg.Admin.Port)
+		admin.RegisterDeadLetters(client)
+		admin.Handle("/metrics", metrics.Handler())
+		admin.RegisterLogLevel(logg)
+		admin.Handle("/healthz", checker.LiveHandler())
+		admin.Handle("/readyz", checker.ReadyHandler())
+		go func() {
+			if err := admin.Start(ctx); err != nil {
+				logg.Error("admin server error: " + err.Error())
+			}
+		}()
+	}
+
+	senderLog := logg.Component("sender")
+	recorder, _ := store.(sender.DeliveryRecorder)
+	webhook := sender.NewWebhookNotifier(senderLog, webhookConfig(cfg.Webhook), recorder)
+	notifiers := map[string]sender.Notifier{
+		storage.ChannelLog:     sender.NewLogNotifier(senderLog),
+		storage.ChannelEmail:   sender.NewEmailNotifier(senderLog),
+		storage.ChannelWebhook: webhook,
+	}
+
+	// SIGHUP и изменение файла конфигурации перечитывают настройки без перезапуска
+	reloader := config.NewReloader(logg.Component("config"), cfg, func() (Config, error) {
+		return NewConfig(configFile, overrides)
+	})
+	reloader.OnReload(func(cfg Config) error {
+		return logg.SetLevels(cfg.Logger.Level, cfg.Logger.Components)
+	}, "logger.level", "logger.components")
+	reloader.OnReload(func(cfg Config) error {
+		webhook.Configure(webhookConfig(cfg.Webhook))
+		return nil
+	}, "webhook")
+	go reloader.Run(ctx, hup, configFile, cfg.Reload.WatchInterval)
+
+	snd := sender.New(senderLog, client, store, notifiers, sender.WithRetryPolicy(retry))
+
+	logg.Info("calendar sender is running...")
+	if err := snd.Run(ctx); err != nil {
+		logg.Error("sender stopped: " + err.Error())
+		os.Exit(1) //nolint:gocritic
+	}
+}
+
+// configCommand обслуживает подкоманды "calendar_sender config ...".
+func configCommand(cfg Config, args []string) error {
+	if len(args) == 1 && args[0] == "print" {
+		// итоговая конфигурация после всех слоёв, секреты скрыты
+		return config.Print(os.Stdout, cfg)
+	}
+	return fmt.Errorf("usage: calendar_sender [-config FILE] [-set key=value ...] config print")
+}
+
+func webhookConfig(c WebhookConf) sender.WebhookConfig {
+	endpoints := make(map[string]sender.WebhookEndpoint, len(c.Endpoints))
+	for userID, e := range c.Endpoints {
+		endpoints[userID] = sender.WebhookEndpoint{URL: e.URL, Secret: e.Secret}
+	}
+	return sender.WebhookConfig{
+		Endpoints:   endpoints,
+		Secret:      c.Secret,
+		Timeout:     c.Timeout,
+		MaxAttempts: c.MaxAttempts,
+		Backoff:     c.Backoff,
+	}
+}
@@ -1,43 +1,234 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strconv"
 
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/migrations"
 	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 )
 
 var (
-	dsn        string
-	migrations string
+	dsn          string
+	dir          string
+	createDir    string
+	dryRun       bool
+	allowMissing bool
 )
 
 func init() {
-	flag.StringVar(&dsn, "dsn", "", "PostgreSQL DSN")
-	flag.StringVar(&migrations, "migrations", "./migrations", "Path to migrations directory")
+	flag.StringVar(&dsn, "dsn", os.Getenv("CALENDAR_DB_DSN"), "PostgreSQL DSN (default $CALENDAR_DB_DSN)")
+	flag.StringVar(&dir, "migrations", "", "Path to migrations directory; empty - migrations embedded into the binary")
+	flag.StringVar(&createDir, "create-dir", "./migrations", "Directory for new migrations (create)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print migrations that would be applied or rolled back without running them")
+	flag.BoolVar(&allowMissing, "allow-missing", false, "Apply migrations missing from the history (out of order)")
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), `Usage: migrate [flags] COMMAND [ARG]
+
+Commands:
+  up            apply all pending migrations (default)
+  down          roll back the last applied migration
+  redo          roll back and re-apply the last applied migration
+  to VERSION    migrate up or down to VERSION
+  status        print applied and pending migrations
+  version       print the current database version
+  create NAME   create a new SQL migration in -create-dir
+
+Flags:
+`)
+	flag.PrintDefaults()
 }
 
 func main() {
 	flag.Parse()
 
+	command := flag.Arg(0)
+	if command == "" {
+		command = "up"
+	}
+
+	if command == "create" {
+		if flag.NArg() != 2 {
+			usage()
+			os.Exit(2)
+		}
+		// новые миграции нумеруются по порядку, как существующие
+		goose.SetSequential(true)
+		if err := goose.Create(nil, createDir, flag.Arg(1), "sql"); err != nil {
+			log.Fatalf("create failed: %v", err)
+		}
+		return
+	}
+
 	if dsn == "" {
-		log.Fatal("missing --dsn")
+		log.Fatal("missing -dsn")
+	}
+
+	source := dir
+	if source == "" {
+		goose.SetBaseFS(migrations.FS)
+		source = migrations.Dir
+	}
+	if err := goose.SetDialect("postgres"); err != nil {
+		log.Fatal(err)
 	}
 
 	db, err := goose.OpenDBWithDriver("postgres", dsn)
 	if err != nil {
-		log.Printf("failed opening DB: %v", err)
-		return
+		log.Fatalf("failed opening DB: %v", err)
 	}
 	defer db.Close()
 
-	fmt.Println("Applying migrations...")
-	if err := goose.Up(db, migrations); err != nil {
-		log.Printf("migration failed: %v", err)
-		return
+	if err := run(db, source, command, flag.Args()[1:]); err != nil {
+		log.Printf("%s failed: %v", command, err)
+		db.Close()
+		os.Exit(1) //nolint:gocritic
+	}
+}
+
+func run(db *sql.DB, source, command string, args []string) error {
+	var opts []goose.OptionsFunc
+	if allowMissing {
+		opts = append(opts, goose.WithAllowMissing())
+	}
+
+	switch command {
+	case "up":
+		if dryRun {
+			return plan(db, source, goose.MaxVersion)
+		}
+		fmt.Println("Applying migrations...")
+		if err := goose.Up(db, source, opts...); err != nil {
+			return err
+		}
+		fmt.Println("Migrations applied successfully")
+	case "down":
+		if dryRun {
+			return planDown(db, source)
+		}
+		return goose.Down(db, source, opts...)
+	case "redo":
+		if dryRun {
+			if err := planDown(db, source); err != nil {
+				return err
+			}
+			fmt.Println("then re-apply it")
+			return nil
+		}
+		return goose.Redo(db, source, opts...)
+	case "to":
+		if len(args) != 1 {
+			return errors.New("usage: migrate to VERSION")
+		}
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], err)
+		}
+		if dryRun {
+			return plan(db, source, version)
+		}
+		current, err := goose.GetDBVersion(db)
+		if err != nil {
+			return err
+		}
+		if version >= current {
+			return goose.UpTo(db, source, version, opts...)
+		}
+		return goose.DownTo(db, source, version, opts...)
+	case "status":
+		return goose.Status(db, source, opts...)
+	case "version":
+		return goose.Version(db, source, opts...)
+	default:
+		usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+// plan печатает миграции, которые понадобится применить или откатить, чтобы база
+// оказалась на версии target. База не меняется, в том числе не создаётся таблица версий.
+func plan(db *sql.DB, source string, target int64) error {
+	current, err := dbVersion(db)
+	if err != nil {
+		return err
+	}
+	all, err := goose.CollectMigrations(source, 0, goose.MaxVersion)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("current version: %d\n", current)
+
+	n := 0
+	if target >= current {
+		for _, m := range all {
+			if m.Version > current && m.Version <= target {
+				fmt.Printf("would apply   %d %s\n", m.Version, base(m.Source))
+				n++
+			}
+		}
+	} else {
+		for i := len(all) - 1; i >= 0; i-- {
+			if m := all[i]; m.Version <= current && m.Version > target {
+				fmt.Printf("would roll back %d %s\n", m.Version, base(m.Source))
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		fmt.Println("nothing to do")
+	}
+	return nil
+}
+
+func planDown(db *sql.DB, source string) error {
+	current, err := dbVersion(db)
+	if err != nil {
+		return err
 	}
+	all, err := goose.CollectMigrations(source, 0, goose.MaxVersion)
+	if err != nil {
+		return err
+	}
+	m, err := all.Current(current)
+	if err != nil {
+		fmt.Printf("current version: %d\nnothing to roll back\n", current)
+		return nil
+	}
+	prev := int64(0)
+	if p, err := all.Previous(current); err == nil {
+		prev = p.Version
+	}
+	fmt.Printf("current version: %d\nwould roll back %d %s (to version %d)\n", current, m.Version, base(m.Source), prev)
+	return nil
+}
+
+// dbVersion читает текущую версию только запросами на чтение: goose.GetDBVersion
+// создаёт таблицу версий, а dry-run не должен менять базу.
+func dbVersion(db *sql.DB) (int64, error) {
+	var exists bool
+	if err := db.QueryRow(`SELECT to_regclass($1) IS NOT NULL`, goose.TableName()).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+	var version int64
+	err := db.QueryRow(fmt.Sprintf(`SELECT COALESCE(MAX(version_id), 0) FROM %s WHERE is_applied`,
+		goose.TableName())).Scan(&version)
+	return version, err
+}
 
-	fmt.Println("Migrations applied successfully")
+func base(path string) string {
+	return filepath.Base(path)
 }
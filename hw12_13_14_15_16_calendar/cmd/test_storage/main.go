@@ -110,55 +110,57 @@ func main() {
 
 	calendarApp := app.New(logg, store)
 
-	fmt.Println("=== Тестирование хранилища календаря ===\n")
+	fmt.Print("=== Тестирование хранилища календаря ===\n\n")
 
 	// Создаем тестовые события
 	now := time.Now()
 	events := []storage.Event{
 		{
-			ID:           uuid.New().String(),
-			Title:        "Встреча с командой",
-			At:           now.Add(2 * time.Hour),
-			Duration:     1 * time.Hour,
-			Description:  "Еженедельная встреча с командой разработки",
-			UserID:       "user1",
-			NotifyBefore: 15 * time.Minute,
+			ID:          uuid.New().String(),
+			Title:       "Встреча с командой",
+			At:          now.Add(2 * time.Hour),
+			Duration:    1 * time.Hour,
+			Description: "Еженедельная встреча с командой разработки",
+			UserID:      "user1",
+			Reminders: []storage.Reminder{
+				{Offset: 15 * time.Minute, Channel: storage.ChannelLog},
+				{Offset: 24 * time.Hour, Channel: storage.ChannelEmail},
+			},
 		},
 		{
-			ID:           uuid.New().String(),
-			Title:        "Презентация проекта",
-			At:           now.Add(24 * time.Hour),
-			Duration:     2 * time.Hour,
-			Description:  "Презентация нового проекта клиенту",
-			UserID:       "user1",
-			NotifyBefore: 30 * time.Minute,
+			ID:          uuid.New().String(),
+			Title:       "Презентация проекта",
+			At:          now.Add(24 * time.Hour),
+			Duration:    2 * time.Hour,
+			Description: "Презентация нового проекта клиенту",
+			UserID:      "user1",
+			Reminders:   []storage.Reminder{{Offset: 30 * time.Minute, Channel: storage.ChannelLog}},
 		},
 		{
-			ID:           uuid.New().String(),
-			Title:        "Обед",
-			At:           now.Add(6 * time.Hour),
-			Duration:     1 * time.Hour,
-			Description:  "Обед с коллегами",
-			UserID:       "user2",
-			NotifyBefore: 0,
+			ID:          uuid.New().String(),
+			Title:       "Обед",
+			At:          now.Add(6 * time.Hour),
+			Duration:    1 * time.Hour,
+			Description: "Обед с коллегами",
+			UserID:      "user2",
 		},
 		{
-			ID:           uuid.New().String(),
-			Title:        "Встреча через неделю",
-			At:           now.Add(7 * 24 * time.Hour),
-			Duration:     30 * time.Minute,
-			Description:  "Встреча через неделю",
-			UserID:       "user1",
-			NotifyBefore: 1 * time.Hour,
+			ID:          uuid.New().String(),
+			Title:       "Встреча через неделю",
+			At:          now.Add(7 * 24 * time.Hour),
+			Duration:    30 * time.Minute,
+			Description: "Встреча через неделю",
+			UserID:      "user1",
+			Reminders:   []storage.Reminder{{Offset: 1 * time.Hour, Channel: storage.ChannelLog}},
 		},
 		{
-			ID:           uuid.New().String(),
-			Title:        "Событие в следующем месяце",
-			At:           now.AddDate(0, 1, 0),
-			Duration:     1 * time.Hour,
-			Description:  "Событие в следующем месяце",
-			UserID:       "user1",
-			NotifyBefore: 24 * time.Hour,
+			ID:          uuid.New().String(),
+			Title:       "Событие в следующем месяце",
+			At:          now.AddDate(0, 1, 0),
+			Duration:    1 * time.Hour,
+			Description: "Событие в следующем месяце",
+			UserID:      "user1",
+			Reminders:   []storage.Reminder{{Offset: 24 * time.Hour, Channel: storage.ChannelLog}},
 		},
 	}
 
@@ -197,6 +199,7 @@ func main() {
 			fmt.Printf("      Описание: %s\n", event.Description)
 			fmt.Printf("      Пользователь: %s\n", event.UserID)
 			fmt.Printf("      Длительность: %v\n", event.Duration)
+			fmt.Printf("      Напоминаний: %d\n", len(event.Reminders))
 		}
 	}
 	fmt.Println()
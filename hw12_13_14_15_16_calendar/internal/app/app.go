@@ -4,9 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
 )
 
+// App - операции с событиями. Если запрос аутентифицирован (auth.ContextWithSubject),
+// владельцем события считается субъект запроса, а не user_id из тела, и чужие события
+// для него не существуют. Без аутентификации user_id клиента принимается как есть.
 type App struct {
 	logger Logger
 	store  Storage
@@ -16,6 +20,8 @@ type Logger interface {
 	Info(msg string)
 	Error(msg string)
 	Debug(msg string)
+	// запись с полями из контекста запроса (request_id, user_id, trace_id)
+	DebugContext(ctx context.Context, msg string, args ...any)
 }
 
 type Storage interface {
@@ -38,36 +44,77 @@ func New(logger Logger, storage Storage) *App {
 }
 
 func (a *App) CreateEvent(ctx context.Context, e storage.Event) error {
-	a.logger.Debug("CreateEvent called")
+	a.logger.DebugContext(ctx, "CreateEvent called", "event_id", e.ID)
+	if subject, ok := auth.SubjectFromContext(ctx); ok {
+		e.UserID = subject
+	}
 	return a.store.CreateEvent(ctx, e)
 }
 
 func (a *App) UpdateEvent(ctx context.Context, e storage.Event) error {
-	a.logger.Debug("UpdateEvent called")
+	a.logger.DebugContext(ctx, "UpdateEvent called", "event_id", e.ID)
+	if subject, ok := auth.SubjectFromContext(ctx); ok {
+		// владелец проверяется по основной базе: реплика может ещё не знать о событии
+		if _, err := a.GetEvent(storage.WithPrimary(ctx), e.ID); err != nil {
+			return err
+		}
+		e.UserID = subject
+	}
 	return a.store.UpdateEvent(ctx, e)
 }
 
 func (a *App) DeleteEvent(ctx context.Context, id string) error {
-	a.logger.Debug("DeleteEvent called")
+	a.logger.DebugContext(ctx, "DeleteEvent called", "event_id", id)
+	if _, ok := auth.SubjectFromContext(ctx); ok {
+		if _, err := a.GetEvent(storage.WithPrimary(ctx), id); err != nil {
+			return err
+		}
+	}
 	return a.store.DeleteEvent(ctx, id)
 }
 
 func (a *App) GetEvent(ctx context.Context, id string) (storage.Event, error) {
-	return a.store.GetEvent(ctx, id)
+	e, err := a.store.GetEvent(ctx, id)
+	if err != nil {
+		return e, err
+	}
+	if subject, ok := auth.SubjectFromContext(ctx); ok && e.UserID != subject {
+		return storage.Event{}, storage.ErrNotFound
+	}
+	return e, nil
 }
 
 func (a *App) ListEvents(ctx context.Context) ([]storage.Event, error) {
-	return a.store.ListEvents(ctx)
+	events, err := a.store.ListEvents(ctx)
+	return ownEvents(ctx, events), err
 }
 
 func (a *App) ListEventsDay(ctx context.Context, dayStart time.Time) ([]storage.Event, error) {
-	return a.store.ListEventsDay(ctx, dayStart)
+	events, err := a.store.ListEventsDay(ctx, dayStart)
+	return ownEvents(ctx, events), err
 }
 
 func (a *App) ListEventsWeek(ctx context.Context, weekStart time.Time) ([]storage.Event, error) {
-	return a.store.ListEventsWeek(ctx, weekStart)
+	events, err := a.store.ListEventsWeek(ctx, weekStart)
+	return ownEvents(ctx, events), err
 }
 
 func (a *App) ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error) {
-	return a.store.ListEventsMonth(ctx, monthStart)
+	events, err := a.store.ListEventsMonth(ctx, monthStart)
+	return ownEvents(ctx, events), err
+}
+
+// ownEvents оставляет только события аутентифицированного субъекта.
+func ownEvents(ctx context.Context, events []storage.Event) []storage.Event {
+	subject, ok := auth.SubjectFromContext(ctx)
+	if !ok || events == nil {
+		return events
+	}
+	out := make([]storage.Event, 0, len(events))
+	for _, e := range events {
+		if e.UserID == subject {
+			out = append(out, e)
+		}
+	}
+	return out
 }
@@ -0,0 +1,168 @@
+// Package auth - аутентификация запросов к API: JWT (HS256 или RS256 с ключами
+// из локального JWKS файла) и статические API ключи. Аутентифицированный субъект
+// кладётся в контекст запроса, откуда его берёт приложение вместо user_id из тела запроса.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoCredentials      = errors.New("missing credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Config - настройки проверки учётных данных. Должен быть задан хотя бы один способ.
+type Config struct {
+	// HS256Secret - общий секрет для токенов HS256
+	HS256Secret string
+	// JWKSFile - путь к JWKS с открытыми RSA ключами для токенов RS256
+	JWKSFile string
+	// Issuer и Audience, если заданы, сверяются с iss и aud токена
+	Issuer   string
+	Audience string
+	// Leeway - допустимое расхождение часов при проверке exp и nbf
+	Leeway time.Duration
+	// APIKeys - статические ключи: субъект -> ключ
+	APIKeys map[string]string
+}
+
+type apiKey struct {
+	subject string
+	hash    [sha256.Size]byte
+}
+
+type Authenticator struct {
+	hsSecret []byte
+	rsKeys   *keySet
+	parser   *jwt.Parser
+	apiKeys  []apiKey
+}
+
+func New(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{}
+	if cfg.HS256Secret != "" {
+		a.hsSecret = []byte(cfg.HS256Secret)
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.rsKeys = keys
+	}
+	for subject, key := range cfg.APIKeys {
+		if subject == "" || key == "" {
+			return nil, errors.New("auth: api key and its subject must not be empty")
+		}
+		a.apiKeys = append(a.apiKeys, apiKey{subject: subject, hash: sha256.Sum256([]byte(key))})
+	}
+	if a.hsSecret == nil && a.rsKeys == nil && len(a.apiKeys) == 0 {
+		return nil, errors.New("auth: no jwt keys or api keys configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+	return a, nil
+}
+
+// Authenticate проверяет JWT или API ключ и возвращает субъект. Строка из трёх частей
+// через точку считается JWT, остальные - API ключом.
+func (a *Authenticator) Authenticate(credential string) (string, error) {
+	if credential == "" {
+		return "", ErrNoCredentials
+	}
+	if strings.Count(credential, ".") == 2 {
+		return a.verifyJWT(credential)
+	}
+	return a.verifyAPIKey(credential)
+}
+
+func (a *Authenticator) verifyJWT(raw string) (string, error) {
+	token, err := a.parser.Parse(raw, a.key)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	sub, err := token.Claims.GetSubject()
+	if err != nil || sub == "" {
+		return "", fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+	return sub, nil
+}
+
+// key выбирает ключ проверки подписи по алгоритму и kid токена.
+func (a *Authenticator) key(t *jwt.Token) (any, error) {
+	switch t.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if a.hsSecret == nil {
+			return nil, errors.New("HS256 tokens are not accepted")
+		}
+		return a.hsSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		if a.rsKeys == nil {
+			return nil, errors.New("RS256 tokens are not accepted")
+		}
+		kid, _ := t.Header["kid"].(string)
+		return a.rsKeys.find(kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", t.Method.Alg())
+	}
+}
+
+func (a *Authenticator) verifyAPIKey(key string) (string, error) {
+	hash := sha256.Sum256([]byte(key))
+	subject := ""
+	// сравниваются все ключи, чтобы время ответа не зависело от того, какой совпал
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+			subject = k.subject
+		}
+	}
+	if subject == "" {
+		return "", ErrInvalidCredentials
+	}
+	return subject, nil
+}
+
+// Credential извлекает учётные данные из заголовка Authorization ("Bearer <token>")
+// или, если его нет, из заголовка X-API-Key.
+func Credential(authorization, apiKey string) string {
+	if scheme, token, ok := strings.Cut(authorization, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(apiKey)
+}
+
+type subjectKey struct{}
+
+// ContextWithSubject сохраняет аутентифицированный субъект; он же попадает в логи как user_id.
+func ContextWithSubject(ctx context.Context, subject string) context.Context {
+	ctx = context.WithValue(ctx, subjectKey{}, subject)
+	return logger.ContextWithUserID(ctx, subject)
+}
+
+// SubjectFromContext возвращает аутентифицированный субъект. false - запрос
+// не аутентифицировался (аутентификация выключена).
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	s, ok := ctx.Value(subjectKey{}).(string)
+	return s, ok && s != ""
+}
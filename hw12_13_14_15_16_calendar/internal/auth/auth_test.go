@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func claims(sub string, exp time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{"sub": sub, "exp": time.Now().Add(exp).Unix(), "iss": "calendar-tests"}
+}
+
+func TestHS256(t *testing.T) {
+	a, err := New(Config{HS256Secret: "secret", Issuer: "calendar-tests"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := a.Authenticate(sign(t, jwt.SigningMethodHS256, []byte("secret"), "", claims("alice", time.Hour)))
+	if err != nil || sub != "alice" {
+		t.Fatalf("expected alice, got %q, %v", sub, err)
+	}
+
+	for name, token := range map[string]string{
+		"wrong secret": sign(t, jwt.SigningMethodHS256, []byte("other"), "", claims("alice", time.Hour)),
+		"expired":      sign(t, jwt.SigningMethodHS256, []byte("secret"), "", claims("alice", -time.Hour)),
+		"no exp":       sign(t, jwt.SigningMethodHS256, []byte("secret"), "", jwt.MapClaims{"sub": "alice"}),
+		"no subject":   sign(t, jwt.SigningMethodHS256, []byte("secret"), "", claims("", time.Hour)),
+		"wrong issuer": sign(t, jwt.SigningMethodHS256, []byte("secret"), "",
+			jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix(), "iss": "evil"}),
+		"alg none": sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", claims("alice", time.Hour)),
+	} {
+		if _, err := a.Authenticate(token); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: expected invalid credentials, got %v", name, err)
+		}
+	}
+}
+
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	t.Helper()
+	var doc struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, k := range keys {
+		doc.Keys = append(doc.Keys, map[string]string{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+	// ключ другого типа пропускается
+	doc.Keys = append(doc.Keys, map[string]string{"kty": "EC", "kid": "ec", "crv": "P-256"})
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRS256(t *testing.T) {
+	k1, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(Config{JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"k1": &k1.PublicKey, "k2": &k2.PublicKey})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := a.Authenticate(sign(t, jwt.SigningMethodRS256, k2, "k2", claims("bob", time.Hour)))
+	if err != nil || sub != "bob" {
+		t.Fatalf("expected bob, got %q, %v", sub, err)
+	}
+	// подпись ключом, не соответствующим kid
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodRS256, k1, "k2", claims("bob", time.Hour))); err == nil {
+		t.Fatal("expected error for mismatched kid")
+	}
+	// без kid при нескольких ключах
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodRS256, k1, "", claims("bob", time.Hour))); err == nil {
+		t.Fatal("expected error for token without kid")
+	}
+	// HS256 не настроен: открытый ключ не должен использоваться как HMAC секрет
+	if _, err := a.Authenticate(sign(t, jwt.SigningMethodHS256, []byte("x"), "k1", claims("bob", time.Hour))); err == nil {
+		t.Fatal("expected HS256 token to be rejected")
+	}
+}
+
+func TestAPIKeys(t *testing.T) {
+	a, err := New(Config{APIKeys: map[string]string{"alice": "key-a", "bob": "key-b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub, err := a.Authenticate("key-b"); err != nil || sub != "bob" {
+		t.Fatalf("expected bob, got %q, %v", sub, err)
+	}
+	if _, err := a.Authenticate("key-c"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected invalid credentials, got %v", err)
+	}
+	if _, err := a.Authenticate(""); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected no credentials, got %v", err)
+	}
+}
+
+func TestNewRequiresKeys(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Fatal("expected error without keys")
+	}
+	if _, err := New(Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Fatal("expected error for missing jwks file")
+	}
+}
+
+func TestCredential(t *testing.T) {
+	cases := []struct {
+		authorization, apiKey, want string
+	}{
+		{"Bearer abc", "", "abc"},
+		{"bearer abc", "key", "abc"},
+		{"Basic abc", "key", "key"},
+		{"", " key ", "key"},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if got := Credential(c.authorization, c.apiKey); got != c.want {
+			t.Errorf("Credential(%q, %q) = %q, want %q", c.authorization, c.apiKey, got, c.want)
+		}
+	}
+}
+
+func TestSubjectContext(t *testing.T) {
+	if _, ok := SubjectFromContext(context.Background()); ok {
+		t.Fatal("expected no subject")
+	}
+	ctx := ContextWithSubject(context.Background(), "alice")
+	if sub, ok := SubjectFromContext(ctx); !ok || sub != "alice" {
+		t.Fatalf("expected alice, got %q", sub)
+	}
+	if logger.UserIDFromContext(ctx) != "alice" {
+		t.Fatal("expected subject to be logged as user_id")
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lru - кэш с ограничением числа элементов, вытесняющий давно не использованные.
+// Устроен как кэш из hw04_lru_cache (очередь + словарь элементов очереди),
+// дополнен удалением по ключу и по условию для инвалидации.
+type lru struct {
+	capacity int
+	mu       sync.Mutex
+	queue    *list.List
+	items    map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	value any
+}
+
+func newLRU(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		queue:    list.New(),
+		items:    make(map[string]*list.Element, capacity),
+	}
+}
+
+// Set сохраняет значение; возвращает true, если ключ уже был в кэше.
+func (c *lru) Set(key string, value any) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return false
+	}
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruItem).value = value
+		c.queue.MoveToFront(el)
+		return true
+	}
+
+	c.items[key] = c.queue.PushFront(&lruItem{key: key, value: value})
+	if c.queue.Len() > c.capacity {
+		c.removeElement(c.queue.Back())
+	}
+	return false
+}
+
+func (c *lru) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.queue.MoveToFront(el)
+	return el.Value.(*lruItem).value, true
+}
+
+func (c *lru) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// RemoveIf удаляет элементы, для которых match вернул true.
+func (c *lru) RemoveIf(match func(key string, value any) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for el := c.queue.Front(); el != nil; {
+		next := el.Next()
+		if it := el.Value.(*lruItem); match(it.key, it.value) {
+			c.removeElement(el)
+		}
+		el = next
+	}
+}
+
+func (c *lru) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queue.Len()
+}
+
+func (c *lru) removeElement(el *list.Element) {
+	c.queue.Remove(el)
+	delete(c.items, el.Value.(*lruItem).key)
+}
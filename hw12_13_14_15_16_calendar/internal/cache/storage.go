@@ -0,0 +1,233 @@
+// Package cache - кэширующая обёртка над app.Storage для частых запросов
+// события по id и событий за день, неделю и месяц.
+package cache
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/app"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+const (
+	defaultTTL      = time.Minute
+	defaultCapacity = 1000
+)
+
+// Storage кэширует GetEvent и ListEventsDay/Week/Month. Записи через Storage сразу удаляют
+// из кэша затронутые элементы: само событие и выборки, в диапазон которых попадает его новая
+// дата или в которых оно уже есть (старая дата). Изменения в обход Storage (другие экземпляры
+// сервиса) и данные отстающей реплики базы могут держаться в кэше до истечения TTL,
+// поэтому чтения, которые должны идти в основную базу (storage.Written - после записи в сессии
+// или storage.WithPrimary), кэш пропускает.
+type Storage struct {
+	next  app.Storage
+	ttl   time.Duration
+	items *lru
+	// номер поколения растёт при каждой инвалидации: результат чтения, начатого
+	// до записи, в кэш не попадает
+	gen atomic.Uint64
+}
+
+type Option func(*Storage)
+
+// WithTTL задаёт время жизни элемента кэша.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Storage) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// WithCapacity ограничивает число элементов кэша (событий и выборок вместе).
+func WithCapacity(n int) Option {
+	return func(s *Storage) {
+		if n > 0 {
+			s.items = newLRU(n)
+		}
+	}
+}
+
+func New(next app.Storage, opts ...Option) *Storage {
+	s := &Storage{
+		next:  next,
+		ttl:   defaultTTL,
+		items: newLRU(defaultCapacity),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type entry struct {
+	expires time.Time
+	// event - для GetEvent; events, from, to - для выборок за [from, to)
+	event  storage.Event
+	events []storage.Event
+	from   time.Time
+	to     time.Time
+}
+
+// affected сообщает, может ли изменение события id с датой at изменить элемент кэша.
+func (e *entry) affected(id string, at time.Time) bool {
+	if e.events == nil {
+		return e.event.ID == id
+	}
+	if !at.IsZero() && !at.Before(e.from) && at.Before(e.to) {
+		return true
+	}
+	for _, ev := range e.events {
+		if ev.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Storage) CreateEvent(ctx context.Context, e storage.Event) error {
+	err := s.next.CreateEvent(ctx, e)
+	s.invalidate(e.ID, e.At)
+	return err
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
+	err := s.next.UpdateEvent(ctx, e)
+	s.invalidate(e.ID, e.At)
+	return err
+}
+
+func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
+	err := s.next.DeleteEvent(ctx, id)
+	s.invalidate(id, time.Time{})
+	return err
+}
+
+// invalidate вызывается и после неудачной записи: при ошибке (например, по таймауту)
+// неизвестно, изменились ли данные.
+func (s *Storage) invalidate(id string, at time.Time) {
+	s.gen.Add(1)
+	s.items.RemoveIf(func(_ string, v any) bool {
+		return v.(*entry).affected(id, at)
+	})
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
+	if storage.Written(ctx) {
+		return s.next.GetEvent(ctx, id)
+	}
+	key := "event:" + id
+	if e, ok := s.lookup(key); ok {
+		metrics.ObserveCache("get_event", true)
+		return cloneEvent(e.event), nil
+	}
+	metrics.ObserveCache("get_event", false)
+
+	gen := s.gen.Load()
+	ev, err := s.next.GetEvent(ctx, id)
+	if err != nil {
+		return ev, err
+	}
+	s.store(key, gen, &entry{event: cloneEvent(ev)})
+	return ev, nil
+}
+
+func (s *Storage) ListEvents(ctx context.Context) ([]storage.Event, error) {
+	return s.next.ListEvents(ctx)
+}
+
+func (s *Storage) ListEventsDay(ctx context.Context, dayStart time.Time) ([]storage.Event, error) {
+	return s.list(ctx, "list_events_day", dayStart, midnight(dayStart), dayStart.Add(24*time.Hour),
+		s.next.ListEventsDay)
+}
+
+func (s *Storage) ListEventsWeek(ctx context.Context, weekStart time.Time) ([]storage.Event, error) {
+	return s.list(ctx, "list_events_week", weekStart, midnight(weekStart), weekStart.Add(7*24*time.Hour),
+		s.next.ListEventsWeek)
+}
+
+func (s *Storage) ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error) {
+	first := time.Date(monthStart.Year(), monthStart.Month(), 1, 0, 0, 0, 0, monthStart.Location())
+	return s.list(ctx, "list_events_month", monthStart, first, first.AddDate(0, 1, 0),
+		s.next.ListEventsMonth)
+}
+
+// list кэширует выборку, начинающуюся с start. Диапазон [from, to) для инвалидации берётся
+// с запасом: хранилища по-разному выравнивают начало дня и недели.
+func (s *Storage) list(ctx context.Context, op string, start, from, to time.Time,
+	fetch func(context.Context, time.Time) ([]storage.Event, error),
+) ([]storage.Event, error) {
+	if storage.Written(ctx) {
+		return fetch(ctx, start)
+	}
+	key := op + ":" + strconv.FormatInt(start.UnixNano(), 10) + ":" + start.Location().String()
+	if e, ok := s.lookup(key); ok {
+		metrics.ObserveCache(op, true)
+		return clone(e.events), nil
+	}
+	metrics.ObserveCache(op, false)
+
+	gen := s.gen.Load()
+	events, err := fetch(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []storage.Event{}
+	}
+	s.store(key, gen, &entry{events: clone(events), from: from, to: to})
+	return events, nil
+}
+
+func (s *Storage) lookup(key string) (*entry, bool) {
+	v, ok := s.items.Get(key)
+	if !ok {
+		return nil, false
+	}
+	e := v.(*entry)
+	if time.Now().After(e.expires) {
+		s.items.Remove(key)
+		return nil, false
+	}
+	return e, true
+}
+
+// store сохраняет результат, если с начала чтения (gen) не было записей.
+func (s *Storage) store(key string, gen uint64, e *entry) {
+	if s.gen.Load() != gen {
+		return
+	}
+	e.expires = time.Now().Add(s.ttl)
+	s.items.Set(key, e)
+	// запись могла пройти между проверкой и Set
+	if s.gen.Load() != gen {
+		s.items.Remove(key)
+	}
+}
+
+// clone копирует выборку вместе с напоминаниями, чтобы вызывающий код не мог изменить кэш.
+func clone(events []storage.Event) []storage.Event {
+	out := make([]storage.Event, 0, len(events))
+	for _, e := range events {
+		out = append(out, cloneEvent(e))
+	}
+	return out
+}
+
+func cloneEvent(e storage.Event) storage.Event {
+	if e.Reminders != nil {
+		e.Reminders = append([]storage.Reminder(nil), e.Reminders...)
+	}
+	return e
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+var _ app.Storage = (*Storage)(nil)
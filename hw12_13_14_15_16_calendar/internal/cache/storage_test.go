@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/app"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+)
+
+// countingStorage считает обращения к хранилищу за выборками.
+type countingStorage struct {
+	app.Storage
+	reads int
+}
+
+func (c *countingStorage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
+	c.reads++
+	return c.Storage.GetEvent(ctx, id)
+}
+
+func (c *countingStorage) ListEventsDay(ctx context.Context, dayStart time.Time) ([]storage.Event, error) {
+	c.reads++
+	return c.Storage.ListEventsDay(ctx, dayStart)
+}
+
+func (c *countingStorage) ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error) {
+	c.reads++
+	return c.Storage.ListEventsMonth(ctx, monthStart)
+}
+
+var (
+	jan = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb = time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newCached(t *testing.T, opts ...Option) (*Storage, *countingStorage) {
+	t.Helper()
+	next := &countingStorage{Storage: memorystorage.New()}
+	return New(next, opts...), next
+}
+
+func TestCacheHitsAndTTL(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCached(t, WithTTL(50*time.Millisecond))
+	if err := s.CreateEvent(ctx, storage.Event{ID: "1", Title: "a", At: jan.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		events, err := s.ListEventsMonth(ctx, jan)
+		if err != nil || len(events) != 1 {
+			t.Fatalf("unexpected result: %v, %v", events, err)
+		}
+		if _, err := s.GetEvent(ctx, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if next.reads != 2 {
+		t.Fatalf("expected 2 reads from storage, got %d", next.reads)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := s.ListEventsMonth(ctx, jan); err != nil {
+		t.Fatal(err)
+	}
+	if next.reads != 3 {
+		t.Fatalf("expected expired entry to be reloaded, got %d reads", next.reads)
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCached(t)
+	e := storage.Event{ID: "1", Title: "a", At: jan.Add(time.Hour)}
+	if err := s.CreateEvent(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	warm := func() {
+		t.Helper()
+		for _, start := range []time.Time{jan, feb} {
+			if _, err := s.ListEventsMonth(ctx, start); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.ListEventsDay(ctx, jan.AddDate(0, 0, 10)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.GetEvent(ctx, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	warm()
+	reads := next.reads
+
+	// перенос события из января в февраль затрагивает оба месяца и само событие,
+	// но не выборку за день, в которой его не было и не будет
+	e.At = feb.Add(time.Hour)
+	e.Title = "b"
+	if err := s.UpdateEvent(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+	warm()
+	if got := next.reads - reads; got != 3 {
+		t.Fatalf("expected 3 reloads after update, got %d", got)
+	}
+	if ev, _ := s.GetEvent(ctx, "1"); ev.Title != "b" {
+		t.Fatalf("expected updated event, got %+v", ev)
+	}
+	if events, _ := s.ListEventsMonth(ctx, feb); len(events) != 1 {
+		t.Fatalf("expected event in february, got %v", events)
+	}
+	if events, _ := s.ListEventsMonth(ctx, jan); len(events) != 0 {
+		t.Fatalf("expected no events in january, got %v", events)
+	}
+
+	reads = next.reads
+	if err := s.DeleteEvent(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := s.ListEventsMonth(ctx, feb); len(events) != 0 {
+		t.Fatalf("expected deleted event to disappear, got %v", events)
+	}
+	if _, err := s.ListEventsMonth(ctx, jan); err != nil {
+		t.Fatal(err)
+	}
+	if got := next.reads - reads; got != 1 {
+		t.Fatalf("expected only february to be reloaded after delete, got %d reads", got)
+	}
+}
+
+func TestCacheCapacity(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCached(t, WithCapacity(2))
+
+	days := []time.Time{jan, jan.AddDate(0, 0, 1), jan.AddDate(0, 0, 2)}
+	for _, d := range days {
+		if _, err := s.ListEventsDay(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.items.Len() != 2 {
+		t.Fatalf("expected 2 cached entries, got %d", s.items.Len())
+	}
+	// первый день вытеснен
+	reads := next.reads
+	if _, err := s.ListEventsDay(ctx, days[0]); err != nil {
+		t.Fatal(err)
+	}
+	if next.reads != reads+1 {
+		t.Fatal("expected evicted entry to be reloaded")
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newCached(t)
+	reminders := []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}}
+	if err := s.CreateEvent(ctx, storage.Event{ID: "1", Title: "a", At: jan, Reminders: reminders}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, _ := s.ListEventsMonth(ctx, jan)
+	events[0].Title = "changed"
+	events[0].Reminders[0].Channel = "changed"
+	events, _ = s.ListEventsMonth(ctx, jan)
+	if events[0].Title != "a" || events[0].Reminders[0].Channel != storage.ChannelLog {
+		t.Fatalf("cached result was modified by caller: %+v", events[0])
+	}
+
+	// и событие, и его напоминания не разделяются с кэшем ни при заполнении, ни при попадании
+	for range 2 {
+		e, _ := s.GetEvent(ctx, "1")
+		e.Reminders[0].Channel = "changed"
+	}
+	if e, _ := s.GetEvent(ctx, "1"); e.Reminders[0].Channel != storage.ChannelLog {
+		t.Fatalf("cached event was modified by caller: %+v", e)
+	}
+}
+
+func TestCacheBypassedForPrimaryReads(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCached(t)
+	if err := s.CreateEvent(ctx, storage.Event{ID: "1", Title: "a", At: jan}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = s.GetEvent(ctx, "1")
+	_, _ = s.ListEventsMonth(ctx, jan)
+
+	primary := storage.WithPrimary(ctx)
+	_, _ = s.GetEvent(primary, "1")
+	_, _ = s.ListEventsMonth(primary, jan)
+	if next.reads != 4 {
+		t.Fatalf("expected primary reads to bypass the cache, got %d reads", next.reads)
+	}
+}
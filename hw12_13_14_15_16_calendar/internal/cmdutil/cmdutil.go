@@ -0,0 +1,145 @@
+// Package cmdutil содержит общие для сервисов календаря (calendar, calendar_scheduler,
+// calendar_sender) секции конфигурации и их преобразование в настройки пакетов.
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	sqlstorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/sql"
+)
+
+type LoggerConf struct {
+	Level string `yaml:"level"`
+	// уровни отдельных компонентов (http, grpc, app, scheduler, sender, queue, ...)
+	Components map[string]string `yaml:"components"`
+	Format     string            `yaml:"format"` // "text" или "json"
+	// куда писать лог; по умолчанию stdout
+	Outputs []LogOutputConf `yaml:"outputs"`
+	// асинхронная запись: при переполнении буфера записи отбрасываются
+	Async      bool `yaml:"async"`
+	BufferSize int  `yaml:"buffer_size"`
+}
+
+type LogOutputConf struct {
+	Type        string        `yaml:"type"` // "stdout", "stderr", "file" или "syslog"
+	Path        string        `yaml:"path"` // путь к файлу или к сокету syslog
+	Tag         string        `yaml:"tag"`
+	MaxSizeMB   int           `yaml:"max_size_mb"`
+	RotateEvery time.Duration `yaml:"rotate_every"`
+	MaxBackups  int           `yaml:"max_backups"`
+	MaxAge      time.Duration `yaml:"max_age"`
+}
+
+// LoggerConfig переводит секцию logger в настройки logger.Open.
+func LoggerConfig(c LoggerConf) logger.Config {
+	outputs := make([]logger.OutputConfig, 0, len(c.Outputs))
+	for _, o := range c.Outputs {
+		outputs = append(outputs, logger.OutputConfig{
+			Type:        o.Type,
+			Path:        o.Path,
+			Tag:         o.Tag,
+			MaxSizeMB:   o.MaxSizeMB,
+			RotateEvery: o.RotateEvery,
+			MaxBackups:  o.MaxBackups,
+			MaxAge:      o.MaxAge,
+		})
+	}
+	return logger.Config{
+		Level:      c.Level,
+		Components: c.Components,
+		Format:     c.Format,
+		Outputs:    outputs,
+		Async:      c.Async,
+		BufferSize: c.BufferSize,
+	}
+}
+
+type DBConf struct {
+	DSN string `yaml:"dsn" secret:"true"`
+	// реплики для чтения событий; пусто - всё читается из основной базы
+	Replicas []string `yaml:"replicas" secret:"true"`
+	// пул соединений; 0 - умолчания database/sql
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	// подключение при старте: попытки и начальная пауза между ними (удваивается)
+	ConnectAttempts int           `yaml:"connect_attempts"`
+	ConnectBackoff  time.Duration `yaml:"connect_backoff"`
+	// ограничение времени одной операции, если у запроса нет более раннего дедлайна
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+	// повторы читающих запросов при обрыве соединения; 0 - без повторов
+	ReadRetries int `yaml:"read_retries"`
+	// применять недостающие миграции при старте (только calendar)
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+// DefaultDBConf возвращает значения секции db по умолчанию.
+func DefaultDBConf() DBConf {
+	return DBConf{
+		ConnectAttempts: 10,
+		ConnectBackoff:  time.Second,
+		ReadRetries:     2,
+	}
+}
+
+// Validate проверяет секцию db для sql хранилища. Ключи в ошибках - относительно
+// секции, полный путь добавляет config.Prefix("db", err).
+func (c DBConf) Validate() error {
+	var errs []error
+	if c.DSN == "" {
+		errs = append(errs, fmt.Errorf("dsn: required for sql storage"))
+	}
+	if c.ConnectAttempts < 1 {
+		errs = append(errs, fmt.Errorf("connect_attempts: must be at least 1"))
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("max_idle_conns: must not exceed max_open_conns %d", c.MaxOpenConns))
+	}
+	for _, v := range []struct {
+		key      string
+		negative bool
+	}{
+		{"max_open_conns", c.MaxOpenConns < 0},
+		{"max_idle_conns", c.MaxIdleConns < 0},
+		{"conn_max_lifetime", c.ConnMaxLifetime < 0},
+		{"conn_max_idle_time", c.ConnMaxIdleTime < 0},
+		{"connect_backoff", c.ConnectBackoff < 0},
+		{"statement_timeout", c.StatementTimeout < 0},
+		{"read_retries", c.ReadRetries < 0},
+	} {
+		if v.negative {
+			errs = append(errs, fmt.Errorf("%s: must not be negative", v.key))
+		}
+	}
+	for i, dsn := range c.Replicas {
+		if dsn == "" {
+			errs = append(errs, fmt.Errorf("replicas[%d]: empty dsn", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StorageOptions переводит секцию db в опции sql хранилища.
+func StorageOptions(c DBConf, logg *logger.Logger) []sqlstorage.Option {
+	return []sqlstorage.Option{
+		sqlstorage.WithLogger(logg),
+		sqlstorage.WithPool(sqlstorage.PoolConfig{
+			MaxOpenConns:    c.MaxOpenConns,
+			MaxIdleConns:    c.MaxIdleConns,
+			ConnMaxLifetime: c.ConnMaxLifetime,
+			ConnMaxIdleTime: c.ConnMaxIdleTime,
+		}),
+		sqlstorage.WithConnectRetry(sqlstorage.RetryConfig{
+			Attempts: c.ConnectAttempts,
+			Backoff:  c.ConnectBackoff,
+		}),
+		// ReadRetries - повторы сверх первой попытки
+		sqlstorage.WithReadRetry(sqlstorage.RetryConfig{Attempts: c.ReadRetries + 1}),
+		sqlstorage.WithStatementTimeout(c.StatementTimeout),
+		sqlstorage.WithReplicas(c.Replicas...),
+	}
+}
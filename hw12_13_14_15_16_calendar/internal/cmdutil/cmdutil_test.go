@@ -0,0 +1,27 @@
+package cmdutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDBConfValidate(t *testing.T) {
+	valid := DefaultDBConf()
+	valid.DSN = "postgres://localhost/calendar"
+	valid.ReadRetries = 0
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	err := DBConf{MaxOpenConns: 2, MaxIdleConns: 5, ReadRetries: -1, Replicas: []string{""}}.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"dsn: required", "max_idle_conns: must not exceed", "read_retries: must not be negative", "connect_attempts: must be at least 1", "replicas[0]",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Package config - общая механика загрузки конфигурации сервисов: файл YAML или TOML,
+// переменные окружения и переопределения из командной строки. Сами структуры
+// и значения по умолчанию описывает каждый сервис в своём config.go.
+//
+// Поля находятся по тегам yaml - и в YAML, и в TOML, и в именах переменных окружения:
+// поле `yaml:"http_port"` секции `yaml:"server"` задаётся ключом server.http_port
+// и переменной <PREFIX>_SERVER_HTTP_PORT.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadFile читает файл конфигурации в dst. Формат определяется по расширению:
+// .toml - TOML, остальные - YAML. Неизвестные ключи считаются ошибкой,
+// чтобы опечатка в имени настройки не превращалась в молча применённое значение по умолчанию.
+func LoadFile(path string, dst any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		// TOML приводим к YAML, чтобы у структур был один набор тегов
+		var raw map[string]any
+		if _, err := toml.Decode(string(data), &raw); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		if data, err = yaml.Marshal(raw); err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// ApplyEnv применяет переменные окружения <prefix>_<SECTION>_<FIELD>. Поддерживаются строки,
+// числа, bool, time.Duration, списки строк через запятую и map[string]string в виде "k=v,k2=v2".
+// Списки и словари структур (например, выводы логгера) задаются только в файле.
+func ApplyEnv(prefix string, dst any) error {
+	var errs []error
+	walk(reflect.ValueOf(dst).Elem(), nil, func(v reflect.Value, path []string) {
+		name := envName(prefix, path)
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		if err := setValue(v, s); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func envName(prefix string, path []string) string {
+	return strings.ToUpper(prefix + "_" + strings.Join(path, "_"))
+}
+
+// Overrides - переопределения из командной строки (-set key=value, флаг можно повторять).
+// Ключ - путь по тегам через точку: server.http_port, logger.components.http.
+type Overrides []string
+
+func (o *Overrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *Overrides) Set(s string) error {
+	if !strings.Contains(s, "=") {
+		return fmt.Errorf("expected key=value, got %q", s)
+	}
+	*o = append(*o, s)
+	return nil
+}
+
+// Apply применяет переопределения к dst по порядку.
+func (o Overrides) Apply(dst any) error {
+	for _, kv := range o {
+		key, value, _ := strings.Cut(kv, "=")
+		if err := Set(dst, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Set присваивает значение полю по пути вида server.http_port.
+func Set(dst any, key, value string) error {
+	v := reflect.ValueOf(dst).Elem()
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		switch {
+		case v.Kind() == reflect.Struct:
+			f, ok := fieldByTag(v, part)
+			if !ok {
+				return fmt.Errorf("%s: unknown key", key)
+			}
+			v = f
+		case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String &&
+			v.Type().Elem().Kind() == reflect.String && i == len(parts)-1:
+			// отдельный элемент словаря: logger.components.http=debug
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			v.SetMapIndex(reflect.ValueOf(part).Convert(v.Type().Key()), reflect.ValueOf(value).Convert(v.Type().Elem()))
+			return nil
+		default:
+			return fmt.Errorf("%s: unknown key", key)
+		}
+	}
+	if v.Kind() == reflect.Struct && v.Type() != durationType {
+		return fmt.Errorf("%s: is a section, not a value", key)
+	}
+	if err := setValue(v, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+// walk вызывает fn для каждого поля-значения (не секции), которое можно задать строкой.
+func walk(v reflect.Value, path []string, fn func(v reflect.Value, path []string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := tagName(t.Field(i))
+		if !ok {
+			continue
+		}
+		f := v.Field(i)
+		p := append(append([]string(nil), path...), name)
+		if f.Kind() == reflect.Struct && f.Type() != durationType {
+			walk(f, p, fn)
+			continue
+		}
+		if settable(f.Type()) {
+			fn(f, p)
+		}
+	}
+}
+
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if n, ok := tagName(t.Field(i)); ok && n == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func tagName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return strings.ToLower(f.Name), true
+	}
+	return name, true
+}
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := splitList(s)
+		list := reflect.MakeSlice(v.Type(), 0, len(items))
+		for _, item := range items {
+			list = reflect.Append(list, reflect.ValueOf(item).Convert(v.Type().Elem()))
+		}
+		v.Set(list)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, item := range splitList(s) {
+			k, val, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("expected key=value, got %q", item)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)).Convert(v.Type().Key()),
+				reflect.ValueOf(strings.TrimSpace(val)).Convert(v.Type().Elem()))
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Prefix добавляет имя секции к ошибкам проверки, в том числе к каждой из объединённых errors.Join.
+func Prefix(section string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint
+		errs := joined.Unwrap()
+		out := make([]error, 0, len(errs))
+		for _, e := range errs {
+			out = append(out, Prefix(section, e))
+		}
+		return errors.Join(out...)
+	}
+	return fmt.Errorf("%s.%w", section, err)
+}
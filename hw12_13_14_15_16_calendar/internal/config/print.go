@@ -0,0 +1,106 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted совпадает с заменой пароля в url.URL.Redacted
+const redacted = "xxxxx"
+
+var passwordParam = regexp.MustCompile(`(password=)\S+`)
+
+// Print выводит конфигурацию в YAML, скрывая значения полей с тегом `secret:"true"`.
+// В строках подключения (postgres://, amqp://, "host=... password=...") скрывается только пароль,
+// остальное оставлено для диагностики.
+func Print(w io.Writer, cfg any) error {
+	n, err := toNode(reflect.ValueOf(cfg), false)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func toNode(v reflect.Value, secret bool) (*yaml.Node, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return scalar(nil)
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != durationType:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := tagName(t.Field(i))
+			if !ok {
+				continue
+			}
+			val, err := toNode(v.Field(i), t.Field(i).Tag.Get("secret") == "true")
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, val)
+		}
+		return n, nil
+	case v.Kind() == reflect.Map && (v.Type().Elem().Kind() == reflect.Struct || secret):
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			val, err := toNode(v.MapIndex(k), secret)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k.String()}, val)
+		}
+		return n, nil
+	case v.Kind() == reflect.Slice && (v.Type().Elem().Kind() == reflect.Struct || secret):
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := 0; i < v.Len(); i++ {
+			val, err := toNode(v.Index(i), secret)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, val)
+		}
+		return n, nil
+	case secret && v.Kind() == reflect.String:
+		return scalar(Redact(v.String()))
+	}
+	return scalar(v.Interface())
+}
+
+func scalar(v any) (*yaml.Node, error) {
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Redact скрывает секрет. Пустое значение остаётся пустым, чтобы было видно, что секрет не задан.
+func Redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	if u, err := url.Parse(s); err == nil && u.Scheme != "" && u.User != nil {
+		return u.Redacted()
+	}
+	if passwordParam.MatchString(s) {
+		return passwordParam.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return redacted
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Logger interface {
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+}
+
+// Change - изменение одного значения конфигурации. Значения секретов скрыты.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+// unset обозначает значение, которого нет в одной из сравниваемых конфигураций
+// (элемент словаря или списка).
+const unset = "<unset>"
+
+// Diff сравнивает две конфигурации одного типа и возвращает изменённые значения, упорядоченные по ключу.
+func Diff(old, new any) []Change {
+	a := make(map[string]leaf)
+	b := make(map[string]leaf)
+	flatten(reflect.ValueOf(old), "", false, a)
+	flatten(reflect.ValueOf(new), "", false, b)
+
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, k := range keys {
+		va, okA := a[k]
+		vb, okB := b[k]
+		if okA && okB && va.raw == vb.raw {
+			continue
+		}
+		c := Change{Key: k, Old: unset, New: unset}
+		if okA {
+			c.Old = va.shown
+		}
+		if okB {
+			c.New = vb.shown
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+type leaf struct {
+	raw   string
+	shown string
+}
+
+func flatten(v reflect.Value, key string, secret bool, out map[string]leaf) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct && v.Type() != durationType:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := tagName(t.Field(i))
+			if !ok {
+				continue
+			}
+			flatten(v.Field(i), join(key, name), t.Field(i).Tag.Get("secret") == "true", out)
+		}
+	case v.Kind() == reflect.Map:
+		for _, k := range v.MapKeys() {
+			flatten(v.MapIndex(k), join(key, fmt.Sprint(k.Interface())), secret, out)
+		}
+	case v.Kind() == reflect.Slice && (v.Type().Elem().Kind() == reflect.Struct || secret):
+		// списки секретов сравниваются поэлементно, чтобы каждый элемент был скрыт
+		for i := 0; i < v.Len(); i++ {
+			flatten(v.Index(i), fmt.Sprintf("%s[%d]", key, i), secret, out)
+		}
+	default:
+		raw := fmt.Sprint(v.Interface())
+		shown := raw
+		if secret && v.Kind() == reflect.String {
+			shown = Redact(raw)
+		}
+		out[key] = leaf{raw: raw, shown: shown}
+	}
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// Reloader перечитывает конфигурацию по SIGHUP и при изменении файла. Новая конфигурация,
+// которая не загрузилась или не прошла проверку, отклоняется целиком. Изменения пишутся в лог;
+// перезагружаемые настройки применяют функции, зарегистрированные через OnReload,
+// об изменении остальных Reloader предупреждает - они вступят в силу после перезапуска.
+// Настройки, которые не удалось применить, применяются повторно при следующей перезагрузке.
+type Reloader[T any] struct {
+	logger Logger
+	load   func() (T, error)
+
+	mu       sync.Mutex
+	current  T
+	appliers []applier[T]
+}
+
+type applier[T any] struct {
+	keys  []string
+	apply func(T) error
+	// applied - конфигурация, которую apply последний раз применил успешно. Если apply
+	// вернул ошибку, она не меняется, и при следующей перезагрузке apply вызывается снова.
+	applied T
+}
+
+func NewReloader[T any](logger Logger, current T, load func() (T, error)) *Reloader[T] {
+	return &Reloader[T]{
+		logger:  logger,
+		load:    load,
+		current: current,
+	}
+}
+
+// OnReload регистрирует применение настроек keys - ключей или целых секций,
+// например "logger.level" или "webhook". apply вызывается с новой конфигурацией,
+// только если изменилось что-то из keys.
+func (r *Reloader[T]) OnReload(apply func(T) error, keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, applier[T]{keys: keys, apply: apply, applied: r.current})
+}
+
+// Current возвращает последнюю принятую конфигурацию.
+func (r *Reloader[T]) Current() T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Reload загружает конфигурацию и применяет изменения.
+func (r *Reloader[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		r.logger.Error("config reload rejected: " + err.Error())
+		return err
+	}
+
+	// настройка применяется, если отличается от применённой этим applier в последний раз:
+	// так повторяются и те, что не удалось применить при прошлой перезагрузке
+	pending := make([]bool, len(r.appliers))
+	anyPending := false
+	for i, a := range r.appliers {
+		for _, c := range Diff(a.applied, next) {
+			if matches(c.Key, a.keys) {
+				pending[i], anyPending = true, true
+				break
+			}
+		}
+	}
+
+	changes := Diff(r.current, next)
+	if len(changes) == 0 && !anyPending {
+		r.logger.Info("config reloaded: no changes")
+		return nil
+	}
+	for _, c := range changes {
+		reloadable := false
+		for _, a := range r.appliers {
+			if matches(c.Key, a.keys) {
+				reloadable = true
+				break
+			}
+		}
+		if reloadable {
+			r.logger.Info("config changed: " + c.String())
+			continue
+		}
+		r.logger.Warn("config changed: " + c.String() + " (requires restart)")
+	}
+	r.current = next
+
+	var errs []error
+	for i := range r.appliers {
+		a := &r.appliers[i]
+		if !pending[i] {
+			continue
+		}
+		if err := a.apply(next); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		a.applied = next
+	}
+	if err := errors.Join(errs...); err != nil {
+		r.logger.Error("failed to apply reloaded config: " + err.Error())
+		return err
+	}
+	r.logger.Info("config reloaded")
+	return nil
+}
+
+func matches(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if key == p || strings.HasPrefix(key, p+".") || strings.HasPrefix(key, p+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Run перезагружает конфигурацию по SIGHUP из канала hup (см. NotifyHangup; nil - подписка
+// при запуске Run), а если задан interval - ещё и при изменении файла path (время изменения
+// или размер). Работает до отмены контекста.
+func (r *Reloader[T]) Run(ctx context.Context, hup <-chan os.Signal, path string, interval time.Duration) {
+	var paths []string
+	if path != "" {
+		paths = []string{path}
+	}
+	Watch(ctx, interval, paths, hup, func(hup bool) error {
+		if hup {
+			r.logger.Info("SIGHUP received, reloading config")
+		} else {
+			r.logger.Info("config file changed, reloading config")
+		}
+		// неверный файл не перечитывается на каждом тике, а ждёт следующего изменения;
+		// неприменённые настройки повторяются при следующей перезагрузке
+		_ = r.Reload()
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// NotifyHangup начинает принимать SIGHUP и возвращает канал для Watch (Reloader.Run).
+// Пока SIGHUP никто не принимает, он завершает процесс, поэтому NotifyHangup вызывается
+// в начале main, до подключения к базе, очереди и загрузки сертификатов. Сигнал,
+// пришедший до запуска Watch, ждёт в канале и не теряется.
+func NotifyHangup() <-chan os.Signal {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	return hup
+}
+
+// Watch вызывает fn по SIGHUP из канала hup (hup = true), а если interval > 0 - ещё и при
+// изменении любого из файлов paths (hup = false). При nil канале Watch подписывается на SIGHUP
+// сам. Изменение считается обработанным, только если fn вернул nil: иначе fn вызывается снова
+// на следующем тике, даже если файлы больше не менялись (например, сертификат уже записан,
+// а ключ ещё нет). Работает до отмены контекста.
+func Watch(ctx context.Context, interval time.Duration, paths []string, hup <-chan os.Signal,
+	fn func(hup bool) error,
+) {
+	if hup == nil {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGHUP)
+		defer signal.Stop(ch)
+		hup = ch
+	}
+
+	files := newFileWatcher(paths...)
+	var tick <-chan time.Time
+	if len(paths) > 0 && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			// файлы перечитываются целиком, поэтому после успеха их изменения уже учтены
+			state, _ := files.check()
+			if fn(true) == nil {
+				files.commit(state)
+			}
+		case <-tick:
+			state, changed := files.check()
+			if changed && fn(false) == nil {
+				files.commit(state)
+			}
+		}
+	}
+}
+
+// fileWatcher замечает изменения файлов по времени изменения и размеру.
+// Отсутствующий файл считается пустым, поэтому его появление и удаление - тоже изменения.
+type fileWatcher struct {
+	paths []string
+	state []fileState
+}
+
+func newFileWatcher(paths ...string) *fileWatcher {
+	w := &fileWatcher{paths: paths}
+	w.state, _ = w.check()
+	return w
+}
+
+// check возвращает текущее состояние файлов и сообщает, отличается ли оно
+// от сохранённого последним commit.
+func (w *fileWatcher) check() ([]fileState, bool) {
+	state := make([]fileState, len(w.paths))
+	changed := false
+	for i, p := range w.paths {
+		state[i] = stat(p)
+		if i >= len(w.state) || !state[i].same(w.state[i]) {
+			changed = true
+		}
+	}
+	return state, changed
+}
+
+// commit запоминает состояние файлов, изменения которого обработаны.
+func (w *fileWatcher) commit(state []fileState) {
+	w.state = state
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileState) same(o fileState) bool {
+	return s.modTime.Equal(o.modTime) && s.size == o.size
+}
+
+func stat(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: fi.ModTime(), size: fi.Size()}
+}
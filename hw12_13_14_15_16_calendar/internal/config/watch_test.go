@@ -0,0 +1,174 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, "a.yaml", "a: 1\n")
+	b := filepath.Join(dir, "b.pem")
+
+	w := newFileWatcher(a, b)
+	if _, changed := w.check(); changed {
+		t.Fatal("expected no changes right after start")
+	}
+
+	// время изменения может совпасть с прежним на файловых системах с грубой точностью
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(a, future, future); err != nil {
+		t.Fatal(err)
+	}
+	state, changed := w.check()
+	if !changed {
+		t.Fatal("expected changed modification time to be detected")
+	}
+	if _, changed := w.check(); !changed {
+		t.Fatal("expected change to be reported until committed")
+	}
+	w.commit(state)
+	if _, changed := w.check(); changed {
+		t.Fatal("expected no changes after commit")
+	}
+
+	if err := os.WriteFile(b, []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	state, changed = w.check()
+	if !changed {
+		t.Fatal("expected created file to be detected")
+	}
+	w.commit(state)
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, changed := w.check(); !changed {
+		t.Fatal("expected removed file to be detected")
+	}
+}
+
+func TestWatchRetriesFailedChange(t *testing.T) {
+	path := writeFile(t, "server.crt", "v1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan struct{}, 16)
+	var failed bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(ctx, 10*time.Millisecond, []string{path}, nil, func(bool) error {
+			calls <- struct{}{}
+			if !failed {
+				failed = true
+				return errors.New("key does not match certificate")
+			}
+			return nil
+		})
+	}()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("v2 longer"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// файл меняется один раз, но неудачная обработка повторяется на следующем тике
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected call %d", i+1)
+		}
+	}
+	// после успеха без новых изменений вызовов нет
+	select {
+	case <-calls:
+		t.Fatal("expected no calls after successful handling")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "config.yaml", "a: 1\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan bool, 4)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(ctx, 10*time.Millisecond, []string{path}, nil, func(hup bool) error {
+			events <- hup
+			return nil
+		})
+	}()
+	time.Sleep(30 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("a: 22\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case hup := <-events:
+		if hup {
+			t.Fatal("expected file change, got SIGHUP")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected file change to be detected")
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case hup := <-events:
+		if !hup {
+			t.Fatal("expected SIGHUP, got file change")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected SIGHUP to be handled")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestWatchHandlesEarlyHangup(t *testing.T) {
+	// сигнал приходит до запуска Watch, пока процесс ещё подключается к базе и очереди
+	hup := NotifyHangup()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan bool, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Watch(ctx, 0, nil, hup, func(hup bool) error {
+			events <- hup
+			return nil
+		})
+	}()
+
+	select {
+	case hup := <-events:
+		if !hup {
+			t.Fatal("expected SIGHUP")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected early SIGHUP to be handled once Watch starts")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,229 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Статусы в ответах /healthz и /readyz.
+const (
+	StatusOK       = "ok"
+	StatusFailing  = "failing"
+	StatusDraining = "draining"
+)
+
+const defaultTimeout = 2 * time.Second
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+}
+
+// CheckFunc проверяет одну зависимость: nil - зависимость доступна.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Report - результат проверки готовности.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Checker отвечает на проверки живости и готовности по HTTP (/healthz, /readyz)
+// и по протоколу grpc.health.v1. Живость означает только, что процесс отвечает;
+// готовность - что доступны все зарегистрированные зависимости и процесс не останавливается.
+type Checker struct {
+	logger   Logger
+	timeout  time.Duration
+	mu       sync.RWMutex
+	checks   []check
+	draining atomic.Bool
+	grpc     *health.Server
+	ready    atomic.Bool
+}
+
+func New(logger Logger, timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	c := &Checker{
+		logger:  logger,
+		timeout: timeout,
+		grpc:    health.NewServer(),
+	}
+	c.ready.Store(true)
+	return c
+}
+
+// Add регистрирует проверку зависимости под именем name.
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Drain переводит процесс в состояние остановки: готовность сразу становится отрицательной,
+// чтобы балансировщик перестал направлять запросы до того, как серверы начнут закрываться.
+func (c *Checker) Drain() {
+	if c.draining.Swap(true) {
+		return
+	}
+	c.logger.Info("health: draining, readiness is off")
+	c.grpc.Shutdown()
+}
+
+// Draining сообщает, вызван ли Drain.
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Check выполняет все проверки параллельно, каждую - не дольше таймаута.
+func (c *Checker) Check(ctx context.Context) Report {
+	if c.draining.Load() {
+		return Report{Status: StatusDraining}
+	}
+
+	c.mu.RLock()
+	checks := append([]check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, ch := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, ch.fn)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+	for i, ch := range checks {
+		if results[i] != nil {
+			report.Status = StatusFailing
+			report.Checks[ch.name] = results[i].Error()
+			continue
+		}
+		report.Checks[ch.name] = StatusOK
+	}
+	return report
+}
+
+// run выполняет проверку и не ждёт её дольше, чем живёт контекст:
+// зависшая проверка не должна задерживать ответ пробе.
+func run(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// LiveHandler обслуживает /healthz.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		respondJSON(w, http.StatusOK, Report{Status: StatusOK})
+	})
+}
+
+// ReadyHandler обслуживает /readyz: 200, если процесс готов принимать запросы, иначе 503.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		respondJSON(w, status, report)
+	})
+}
+
+// GRPC возвращает реализацию grpc.health.v1.Health. Статус в ней обновляет Watch.
+func (c *Checker) GRPC() healthpb.HealthServer {
+	return c.grpc
+}
+
+// Watch периодически выполняет проверки и обновляет статус gRPC health до отмены контекста.
+// Смены состояния пишутся в лог.
+func (c *Checker) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		c.update(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) update(ctx context.Context) {
+	if c.draining.Load() {
+		return
+	}
+	report := c.Check(ctx)
+	ready := report.Status == StatusOK
+	if ready {
+		c.grpc.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	} else {
+		c.grpc.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	if c.ready.Swap(ready) == ready {
+		return
+	}
+	if ready {
+		c.logger.Info("health: ready")
+		return
+	}
+	c.logger.Error("health: not ready: " + failed(report))
+}
+
+// failed перечисляет упавшие проверки в стабильном порядке.
+func failed(r Report) string {
+	names := make([]string, 0, len(r.Checks))
+	for name, res := range r.Checks {
+		if res != StatusOK {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var out string
+	for i, name := range names {
+		if i > 0 {
+			out += ", "
+		}
+		out += name + ": " + r.Checks[name]
+	}
+	return out
+}
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
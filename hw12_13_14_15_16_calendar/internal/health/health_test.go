@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func readyz(t *testing.T, c *Checker) (int, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	c.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestReadyz(t *testing.T) {
+	c := New(logger.New("error"), time.Second)
+	var dbErr error
+	c.Add("storage", func(context.Context) error { return dbErr })
+	c.Add("queue", func(context.Context) error { return nil })
+
+	code, report := readyz(t, c)
+	if code != http.StatusOK || report.Status != StatusOK {
+		t.Fatalf("expected ready, got %d %+v", code, report)
+	}
+
+	dbErr = errors.New("connection refused")
+	code, report = readyz(t, c)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if report.Checks["storage"] != "connection refused" || report.Checks["queue"] != StatusOK {
+		t.Fatalf("unexpected checks: %+v", report.Checks)
+	}
+}
+
+func TestReadyzTimeout(t *testing.T) {
+	c := New(logger.New("error"), 20*time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	c.Add("storage", func(context.Context) error {
+		<-block
+		return nil
+	})
+
+	start := time.Now()
+	code, report := readyz(t, c)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", code)
+	}
+	if report.Checks["storage"] != context.DeadlineExceeded.Error() {
+		t.Fatalf("unexpected checks: %+v", report.Checks)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("hanging check delayed the probe")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	c := New(logger.New("error"), time.Second)
+	c.Add("storage", func(context.Context) error { return nil })
+	ctx := context.Background()
+
+	c.update(ctx)
+	resp, err := c.GRPC().Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("expected SERVING, got %v %v", resp, err)
+	}
+
+	c.Drain()
+
+	code, report := readyz(t, c)
+	if code != http.StatusServiceUnavailable || report.Status != StatusDraining {
+		t.Fatalf("expected draining, got %d %+v", code, report)
+	}
+	resp, err = c.GRPC().Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("expected NOT_SERVING, got %v %v", resp, err)
+	}
+
+	// живость при остановке не меняется
+	rec := httptest.NewRecorder()
+	c.LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /healthz 200, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultBufferSize = 1024
+
+// AsyncWriter пишет в нижележащий вывод из отдельной горутины. Write никогда не блокируется:
+// если буфер заполнен, запись отбрасывается и учитывается в Dropped. О потерях
+// в лог пишется отдельная запись уровня WARN в формате логгера, как только вывод
+// успевает их догнать.
+type AsyncWriter struct {
+	out     io.Writer
+	format  string
+	ch      chan []byte
+	dropped atomic.Uint64
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsyncWriter создаёт писатель; format - формат логгера (FormatText или FormatJSON),
+// в нём пишется запись о потерях.
+func NewAsyncWriter(out io.Writer, bufferSize int, format string) *AsyncWriter {
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	w := &AsyncWriter{
+		out:    out,
+		format: format,
+		ch:     make(chan []byte, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	// вызывающий может переиспользовать p после возврата
+	buf := append([]byte(nil), p...)
+	select {
+	case w.ch <- buf:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped возвращает общее количество отброшенных записей.
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close дописывает буфер и останавливает горутину записи. Нижележащий вывод не закрывается.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.ch)
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	var reported uint64
+	for p := range w.ch {
+		_, _ = w.out.Write(p)
+		if d := w.dropped.Load(); d != reported && len(w.ch) == 0 {
+			_, _ = w.out.Write(encodeEntry(w.format, entry{
+				time:   time.Now(),
+				level:  WARN,
+				msg:    "logger: records dropped, buffer is full",
+				fields: []Field{{Key: "dropped", Value: d - reported}},
+			}))
+			reported = d
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	userIDKey
+)
+
+// ContextWithRequestID сохраняет идентификатор запроса для логов.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestIDFromContext возвращает идентификатор запроса или пустую строку.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// ContextWithUserID сохраняет идентификатор пользователя для логов.
+func ContextWithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey, id)
+}
+
+// UserIDFromContext возвращает идентификатор пользователя или пустую строку.
+func UserIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey).(string)
+	return id
+}
+
+// contextArgs собирает поля запроса из контекста.
+func contextArgs(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	var args []any
+	if id := RequestIDFromContext(ctx); id != "" {
+		args = append(args, "request_id", id)
+	}
+	if id := UserIDFromContext(ctx); id != "" {
+		args = append(args, "user_id", id)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		args = append(args, "trace_id", sc.TraceID().String())
+	}
+	return args
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const badKey = "!BADKEY"
+
+// Field - поле записи лога.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// fieldsFromArgs превращает список ключ/значение в поля. Значение без ключа
+// записывается под ключом !BADKEY, как это делает log/slog.
+func fieldsFromArgs(args []any) []Field {
+	fields := make([]Field, 0, (len(args)+1)/2)
+	for i := 0; i < len(args); i++ {
+		if f, ok := args[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		key, ok := args[i].(string)
+		if !ok || i+1 >= len(args) {
+			fields = append(fields, Field{Key: badKey, Value: args[i]})
+			continue
+		}
+		fields = append(fields, Field{Key: key, Value: args[i+1]})
+		i++
+	}
+	return fields
+}
+
+type entry struct {
+	time   time.Time
+	level  level
+	msg    string
+	fields []Field
+}
+
+// encodeEntry кодирует запись в заданном формате логгера.
+func encodeEntry(format string, e entry) []byte {
+	if format == FormatJSON {
+		return encodeJSON(e)
+	}
+	return encodeText(e)
+}
+
+// encodeText: 2006-01-02T15:04:05Z07:00 [LEVEL] msg key=value key="value with spaces"
+func encodeText(e entry) []byte {
+	var b bytes.Buffer
+	b.WriteString(e.time.Format(time.RFC3339))
+	b.WriteString(" [")
+	b.WriteString(e.level.String())
+	b.WriteString("] ")
+	b.WriteString(e.msg)
+	for _, f := range e.fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(textValue(f.Value))
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func textValue(v any) string {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case time.Time:
+		s = v.Format(time.RFC3339Nano)
+	case time.Duration:
+		s = v.String()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// encodeJSON: {"time":"...","level":"INFO","msg":"...","key":"value"}
+func encodeJSON(e entry) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"time":`)
+	writeJSON(&b, e.time.Format(time.RFC3339Nano))
+	b.WriteString(`,"level":`)
+	writeJSON(&b, e.level.String())
+	b.WriteString(`,"msg":`)
+	writeJSON(&b, e.msg)
+	for _, f := range e.fields {
+		b.WriteByte(',')
+		writeJSON(&b, f.Key)
+		b.WriteByte(':')
+		writeJSON(&b, jsonValue(f.Value))
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func writeJSON(b *bytes.Buffer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
@@ -1,11 +1,13 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,53 +21,265 @@ const (
 )
 
 func parseLevel(s string) level {
-	switch strings.ToLower(s) {
+	lv, err := lookupLevel(s)
+	if err != nil {
+		return INFO
+	}
+	return lv
+}
+
+// lookupLevel разбирает имя уровня; в отличие от parseLevel неизвестный уровень - ошибка.
+func lookupLevel(s string) (level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "debug":
-		return DEBUG
+		return DEBUG, nil
+	case "info", "":
+		return INFO, nil
 	case "warn", "warning":
-		return WARN
+		return WARN, nil
 	case "error":
-		return ERROR
+		return ERROR, nil
 	default:
-		return INFO
+		return INFO, fmt.Errorf("unknown log level %q", s)
+	}
+}
+
+func (lv level) String() string {
+	switch lv {
+	case DEBUG:
+		return "DEBUG"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	default:
+		return "INFO"
 	}
 }
 
+// Форматы вывода.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Logger пишет записи с уровнем, сообщением и полями ключ/значение.
+// Дочерние логгеры (With, WithContext, Component) разделяют вывод и уровень с корневым.
+// Уровень можно менять на лету, в том числе отдельно для компонентов.
 type Logger struct {
-	out   io.Writer
-	level level
-	mu    sync.Mutex
+	out    io.Writer
+	level  atomic.Int32
+	format string
+	mu     sync.Mutex
+	// уровни компонентов, заменяются целиком
+	overrides atomic.Pointer[map[string]level]
+
+	root      *Logger // nil у корневого логгера
+	component string
+	fields    []Field
+	closers   []io.Closer // выводы, открытые Open
+}
+
+type Option func(*Logger)
+
+// WithFormat задаёт формат записей: FormatText (по умолчанию) или FormatJSON.
+func WithFormat(format string) Option {
+	return func(l *Logger) {
+		l.format = strings.ToLower(format)
+	}
+}
+
+// WithOutput задаёт, куда писать записи; по умолчанию os.Stdout.
+func WithOutput(w io.Writer) Option {
+	return func(l *Logger) {
+		l.out = w
+	}
 }
 
-func New(levelStr string) *Logger {
+func New(levelStr string, opts ...Option) *Logger {
+	l := &Logger{out: os.Stdout}
+	l.level.Store(int32(parseLevel(levelStr)))
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// With возвращает логгер, добавляющий к каждой записи переданные поля.
+// Аргументы - пары ключ/значение, как в log/slog.
+func (l *Logger) With(args ...any) *Logger {
+	if len(args) == 0 {
+		return l
+	}
 	return &Logger{
-		out:   os.Stdout,
-		level: parseLevel(levelStr),
+		root:      l.core(),
+		component: l.component,
+		fields:    append(append([]Field(nil), l.fields...), fieldsFromArgs(args)...),
 	}
 }
 
-func (l *Logger) log(lv level, prefix, msg string) {
-	if lv < l.level {
+// Component возвращает логгер компонента: записи получают поле component,
+// а уровень можно переопределить через SetComponentLevel или SetLevels.
+func (l *Logger) Component(name string) *Logger {
+	child := l.With("component", name)
+	child.component = name
+	return child
+}
+
+// WithContext возвращает логгер с полями запроса из контекста: request_id, user_id и trace_id.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	return l.With(contextArgs(ctx)...)
+}
+
+// core возвращает корневой логгер, владеющий выводом и уровнем.
+func (l *Logger) core() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
+
+func (l *Logger) enabled(lv level) bool {
+	c := l.core()
+	if l.component != "" {
+		if m := c.overrides.Load(); m != nil {
+			if threshold, ok := (*m)[l.component]; ok {
+				return lv >= threshold
+			}
+		}
+	}
+	return lv >= level(c.level.Load())
+}
+
+// SetLevel меняет уровень логгера (и всех дочерних) без перезапуска.
+func (l *Logger) SetLevel(s string) error {
+	lv, err := lookupLevel(s)
+	if err != nil {
+		return err
+	}
+	l.core().level.Store(int32(lv))
+	return nil
+}
+
+// SetComponentLevel переопределяет уровень одного компонента; пустой уровень снимает переопределение.
+func (l *Logger) SetComponentLevel(component, s string) error {
+	c := l.core()
+	var lv level
+	if s != "" {
+		var err error
+		if lv, err = lookupLevel(s); err != nil {
+			return err
+		}
+	}
+	for {
+		old := c.overrides.Load()
+		next := make(map[string]level)
+		if old != nil {
+			for k, v := range *old {
+				next[k] = v
+			}
+		}
+		if s == "" {
+			delete(next, component)
+		} else {
+			next[component] = lv
+		}
+		if c.overrides.CompareAndSwap(old, &next) {
+			return nil
+		}
+	}
+}
+
+// SetLevels заменяет общий уровень и все переопределения компонентов разом.
+// При ошибке в любом из уровней ничего не меняется.
+func (l *Logger) SetLevels(s string, components map[string]string) error {
+	lv, err := lookupLevel(s)
+	if err != nil {
+		return err
+	}
+	overrides := make(map[string]level, len(components))
+	for name, cs := range components {
+		clv, err := lookupLevel(cs)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+		overrides[name] = clv
+	}
+	c := l.core()
+	c.level.Store(int32(lv))
+	c.overrides.Store(&overrides)
+	return nil
+}
+
+// Levels возвращает общий уровень и переопределения компонентов.
+func (l *Logger) Levels() (string, map[string]string) {
+	c := l.core()
+	components := make(map[string]string)
+	if m := c.overrides.Load(); m != nil {
+		for name, lv := range *m {
+			components[name] = strings.ToLower(lv.String())
+		}
+	}
+	return strings.ToLower(level(c.level.Load()).String()), components
+}
+
+func (l *Logger) log(lv level, msg string, fields []Field) {
+	if !l.enabled(lv) {
 		return
 	}
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	ts := time.Now().Format(time.RFC3339)
-	fmt.Fprintf(l.out, "%s [%s] %s\n", ts, prefix, msg)
+	c := l.core()
+	e := entry{
+		time:   time.Now(),
+		level:  lv,
+		msg:    msg,
+		fields: fields,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = c.out.Write(encodeEntry(c.format, e))
+}
+
+func (l *Logger) logArgs(ctx context.Context, lv level, msg string, args []any) {
+	if !l.enabled(lv) {
+		return
+	}
+	fields := l.fields
+	if extra := append(contextArgs(ctx), args...); len(extra) > 0 {
+		fields = append(append([]Field(nil), l.fields...), fieldsFromArgs(extra)...)
+	}
+	l.log(lv, msg, fields)
 }
 
 func (l *Logger) Info(msg string) {
-	l.log(INFO, "INFO", msg)
+	l.log(INFO, msg, l.fields)
 }
 
 func (l *Logger) Error(msg string) {
-	l.log(ERROR, "ERROR", msg)
+	l.log(ERROR, msg, l.fields)
 }
 
 func (l *Logger) Debug(msg string) {
-	l.log(DEBUG, "DEBUG", msg)
+	l.log(DEBUG, msg, l.fields)
 }
 
 func (l *Logger) Warn(msg string) {
-	l.log(WARN, "WARN", msg)
+	l.log(WARN, msg, l.fields)
+}
+
+// InfoContext пишет запись с полями из контекста и парами ключ/значение args.
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.logArgs(ctx, INFO, msg, args)
+}
+
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.logArgs(ctx, ERROR, msg, args)
+}
+
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.logArgs(ctx, DEBUG, msg, args)
+}
+
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.logArgs(ctx, WARN, msg, args)
 }
@@ -2,13 +2,16 @@ package logger
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
 
 func TestLoggerLevels(t *testing.T) {
 	buf := &bytes.Buffer{}
-	l := &Logger{out: buf, level: parseLevel("info")}
+	l := New("info", WithOutput(buf))
 
 	l.Debug("should not appear")
 	l.Info("hello")
@@ -22,3 +25,100 @@ func TestLoggerLevels(t *testing.T) {
 		t.Fatalf("expected messages missing: %s", s)
 	}
 }
+
+func TestLoggerTextFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New("info", WithOutput(buf)).With("component", "http")
+
+	l.InfoContext(context.Background(), "request done", "status", 200, "path", "/api/events?id=1 2")
+
+	s := buf.String()
+	if !strings.Contains(s, "[INFO] request done component=http status=200 path=\"/api/events?id=1 2\"") {
+		t.Fatalf("unexpected text record: %s", s)
+	}
+}
+
+func TestLoggerJSONContextFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New("debug", WithOutput(buf), WithFormat(FormatJSON))
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithUserID(ctx, "user1")
+	l.WithContext(ctx).Debug("loaded")
+	l.ErrorContext(context.Background(), "failed", "err", errors.New("boom"), "odd")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 records, got %d: %s", len(lines), buf.String())
+	}
+
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("invalid json record: %v", err)
+	}
+	if rec["level"] != "DEBUG" || rec["msg"] != "loaded" || rec["request_id"] != "req-1" || rec["user_id"] != "user1" {
+		t.Fatalf("unexpected json record: %v", rec)
+	}
+
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("invalid json record: %v", err)
+	}
+	if rec["err"] != "boom" || rec[badKey] != "odd" {
+		t.Fatalf("unexpected json record: %v", rec)
+	}
+}
+
+func TestLoggerSlogBridge(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New("info", WithOutput(buf))
+	sl := l.Slog().With("lib", "goose").WithGroup("db")
+
+	sl.Debug("hidden")
+	sl.Info("migrated", "version", 3)
+
+	s := buf.String()
+	if strings.Contains(s, "hidden") {
+		t.Fatalf("debug record printed when level=info: %s", s)
+	}
+	if !strings.Contains(s, "[INFO] migrated lib=goose db.version=3") {
+		t.Fatalf("unexpected slog record: %s", s)
+	}
+}
+
+func TestLoggerRuntimeLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := New("info", WithOutput(buf))
+	sched := l.Component("scheduler")
+	child := sched.With("event_id", "1")
+
+	sched.Debug("hidden")
+	if err := l.SetComponentLevel("scheduler", "debug"); err != nil {
+		t.Fatalf("set component level failed: %v", err)
+	}
+	child.Debug("visible")
+	l.Debug("root stays at info")
+
+	s := buf.String()
+	if strings.Contains(s, "hidden") || strings.Contains(s, "root stays at info") {
+		t.Fatalf("unexpected debug records: %s", s)
+	}
+	if !strings.Contains(s, "visible component=scheduler event_id=1") {
+		t.Fatalf("expected component debug record: %s", s)
+	}
+
+	if err := l.SetLevels("error", map[string]string{"sender": "bogus"}); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if lv, comps := l.Levels(); lv != "info" || comps["scheduler"] != "debug" {
+		t.Fatalf("failed SetLevels must not change levels, got %s %v", lv, comps)
+	}
+
+	if err := l.SetLevels("error", nil); err != nil {
+		t.Fatalf("set levels failed: %v", err)
+	}
+	buf.Reset()
+	child.Info("hidden after reset")
+	if buf.Len() != 0 {
+		t.Fatalf("expected overrides to be replaced, got %s", buf.String())
+	}
+}
@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Виды выводов.
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+	OutputSyslog = "syslog"
+)
+
+// Config описывает логгер целиком: уровень, формат и выводы.
+type Config struct {
+	Level      string
+	Components map[string]string // уровни отдельных компонентов, см. Logger.Component
+	Format     string
+	Outputs    []OutputConfig // пусто - stdout
+	// Async включает буферизованную запись в отдельной горутине: при переполнении
+	// буфера записи отбрасываются, а не блокируют вызывающего.
+	Async      bool
+	BufferSize int // записей в буфере, по умолчанию 1024
+}
+
+type OutputConfig struct {
+	Type string // stdout, stderr, file или syslog
+	// file: путь к файлу; syslog: адрес локального сокета (пусто - системный /dev/log)
+	Path string
+	// syslog: тег записей
+	Tag string
+	// ротация файла: по размеру и/или по времени
+	MaxSizeMB   int
+	RotateEvery time.Duration
+	// хранение ротированных файлов: не больше MaxBackups штук и не старше MaxAge
+	MaxBackups int
+	MaxAge     time.Duration
+}
+
+// Validate проверяет конфигурацию, не открывая выводы.
+func (c Config) Validate() error {
+	var errs []error
+	if _, err := lookupLevel(c.Level); err != nil {
+		errs = append(errs, fmt.Errorf("level: %w", err))
+	}
+	for component, lv := range c.Components {
+		if _, err := lookupLevel(lv); err != nil {
+			errs = append(errs, fmt.Errorf("components.%s: %w", component, err))
+		}
+	}
+	switch strings.ToLower(c.Format) {
+	case "", FormatText, FormatJSON:
+	default:
+		errs = append(errs, fmt.Errorf("format: unknown format %q, expected %s or %s", c.Format, FormatText, FormatJSON))
+	}
+	for i, oc := range c.Outputs {
+		switch strings.ToLower(oc.Type) {
+		case "", OutputStdout, OutputStderr, OutputSyslog:
+		case OutputFile:
+			if oc.Path == "" {
+				errs = append(errs, fmt.Errorf("outputs[%d]: file output requires path", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("outputs[%d]: unknown output type %q", i, oc.Type))
+		}
+	}
+	if c.BufferSize < 0 {
+		errs = append(errs, fmt.Errorf("buffer_size: must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Open создаёт логгер по конфигурации. Логгер нужно закрыть через Close,
+// чтобы дописать буфер и закрыть файлы.
+func Open(cfg Config) (*Logger, error) {
+	w, closers, err := openOutputs(cfg.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Async {
+		aw := NewAsyncWriter(w, cfg.BufferSize, strings.ToLower(cfg.Format))
+		w = aw
+		// асинхронный писатель закрываем первым, чтобы он дописал буфер в открытые выводы
+		closers = append([]io.Closer{aw}, closers...)
+	}
+
+	l := New(cfg.Level, WithFormat(cfg.Format), WithOutput(w))
+	l.closers = closers
+	if err := l.SetLevels(cfg.Level, cfg.Components); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Close дописывает буферизованные записи и закрывает выводы.
+func (l *Logger) Close() error {
+	c := l.core()
+	var errs []error
+	for _, closer := range c.closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.closers = nil
+	return errors.Join(errs...)
+}
+
+// Dropped возвращает количество записей, отброшенных асинхронным выводом.
+func (l *Logger) Dropped() uint64 {
+	if aw, ok := l.core().out.(*AsyncWriter); ok {
+		return aw.Dropped()
+	}
+	return 0
+}
+
+func openOutputs(cfgs []OutputConfig) (io.Writer, []io.Closer, error) {
+	if len(cfgs) == 0 {
+		return os.Stdout, nil, nil
+	}
+
+	var writers []io.Writer
+	var closers []io.Closer
+	for _, oc := range cfgs {
+		w, closer, err := openOutput(oc)
+		if err != nil {
+			for _, c := range closers {
+				_ = c.Close()
+			}
+			return nil, nil, err
+		}
+		writers = append(writers, w)
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	if len(writers) == 1 {
+		return writers[0], closers, nil
+	}
+	return fanout(writers), closers, nil
+}
+
+func openOutput(oc OutputConfig) (io.Writer, io.Closer, error) {
+	switch strings.ToLower(oc.Type) {
+	case "", OutputStdout:
+		return os.Stdout, nil, nil
+	case OutputStderr:
+		return os.Stderr, nil, nil
+	case OutputFile:
+		if oc.Path == "" {
+			return nil, nil, fmt.Errorf("file log output requires path")
+		}
+		f, err := NewRotatingFile(oc.Path, RotateConfig{
+			MaxSize:     int64(oc.MaxSizeMB) << 20,
+			RotateEvery: oc.RotateEvery,
+			MaxBackups:  oc.MaxBackups,
+			MaxAge:      oc.MaxAge,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f, nil
+	case OutputSyslog:
+		w, err := dialSyslog(oc.Path, oc.Tag)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open syslog output: %w", err)
+		}
+		return w, w, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown log output %q", oc.Type)
+	}
+}
+
+// fanout пишет в каждый вывод; в отличие от io.MultiWriter ошибка одного вывода
+// не мешает записи в остальные.
+type fanout []io.Writer
+
+func (f fanout) Write(p []byte) (int, error) {
+	var errs []error
+	for _, w := range f {
+		if _, err := w.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
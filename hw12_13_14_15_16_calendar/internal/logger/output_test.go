@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "calendar.log")
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.Local)
+	r, err := NewRotatingFile(path, RotateConfig{MaxSize: 10, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	r.now = func() time.Time { return now }
+	defer r.Close()
+
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Second)
+		if _, err := r.Write([]byte("0123456789")); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups to be kept, got %v", backups)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "0123456789" {
+		t.Fatalf("expected only the last record in current file, got %q", data)
+	}
+}
+
+func TestRotatingFileByTime(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "calendar.log")
+
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.Local)
+	r, err := NewRotatingFile(path, RotateConfig{RotateEvery: time.Hour, MaxAge: 30 * time.Minute})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	r.now = func() time.Time { return now }
+	r.opened = now
+	defer r.Close()
+
+	_, _ = r.Write([]byte("first\n"))
+	now = now.Add(time.Hour)
+	_, _ = r.Write([]byte("second\n"))
+	now = now.Add(time.Hour)
+	_, _ = r.Write([]byte("third\n"))
+
+	// первая копия старше MaxAge и должна быть удалена
+	backups, _ := filepath.Glob(path + ".*")
+	if len(backups) != 1 {
+		t.Fatalf("expected 1 backup within max age, got %v", backups)
+	}
+	data, _ := os.ReadFile(backups[0])
+	if string(data) != "second\n" {
+		t.Fatalf("unexpected backup content %q", data)
+	}
+}
+
+type blockingWriter struct {
+	mu      sync.Mutex
+	release chan struct{}
+	data    strings.Builder
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.data.Write(p)
+}
+
+func TestAsyncWriterDropsWhenFull(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	w := NewAsyncWriter(out, 2, FormatJSON)
+
+	// первая запись забирается горутиной и блокирует её, две ложатся в буфер
+	_, _ = w.Write([]byte("1\n"))
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if _, err := w.Write([]byte("x\n")); err != nil {
+			t.Fatalf("write must not fail: %v", err)
+		}
+	}
+	if w.Dropped() != 3 {
+		t.Fatalf("expected 3 dropped records, got %d", w.Dropped())
+	}
+
+	close(out.release)
+	_ = w.Close()
+
+	// отчёт о потерях - обычная запись в формате логгера
+	lines := strings.Split(strings.TrimSpace(out.data.String()), "\n")
+	var report map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &report); err != nil {
+		t.Fatalf("expected drop report to be a JSON record, got %q", lines[len(lines)-1])
+	}
+	if report["level"] != "WARN" || report["dropped"] != float64(3) {
+		t.Fatalf("unexpected drop report %v", report)
+	}
+}
+
+func TestOpenFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "calendar.log")
+	l, err := Open(Config{
+		Level:   "info",
+		Format:  FormatJSON,
+		Outputs: []OutputConfig{{Type: OutputFile, Path: path}},
+		Async:   true,
+	})
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	l.Info("hello")
+	if err := l.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"msg":"hello"`) {
+		t.Fatalf("expected record in file, got %q", data)
+	}
+
+	if _, err := Open(Config{Outputs: []OutputConfig{{Type: "kafka"}}}); err == nil {
+		t.Fatal("expected error for unknown output")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{Level: "debug", Components: map[string]string{"http": "warn"}, Format: "json"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	invalid := Config{
+		Level:      "verbose",
+		Components: map[string]string{"http": "loud"},
+		Format:     "xml",
+		Outputs:    []OutputConfig{{Type: OutputFile}, {Type: "kafka"}},
+	}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"level", "components.http", "format", "outputs[0]", "outputs[1]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %s, got %v", want, err)
+		}
+	}
+}
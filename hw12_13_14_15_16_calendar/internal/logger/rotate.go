@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000"
+
+type RotateConfig struct {
+	MaxSize     int64         // байт; 0 - без ротации по размеру
+	RotateEvery time.Duration // 0 - без ротации по времени
+	MaxBackups  int           // 0 - хранить все
+	MaxAge      time.Duration // 0 - без ограничения возраста
+}
+
+// RotatingFile - файл лога с ротацией. Текущий файл переименовывается
+// в <имя>.<время>, старые копии удаляются по MaxBackups и MaxAge.
+type RotatingFile struct {
+	path   string
+	cfg    RotateConfig
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+	now    func() time.Time
+}
+
+func NewRotatingFile(path string, cfg RotateConfig) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, cfg: cfg, now: time.Now}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(next int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.cfg.MaxSize > 0 && r.size+next > r.cfg.MaxSize {
+		return true
+	}
+	return r.cfg.RotateEvery > 0 && r.now().Sub(r.opened) >= r.cfg.RotateEvery
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	r.opened = r.now()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	backup := r.path + "." + r.now().Format(backupTimeFormat)
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	return r.cleanup()
+}
+
+// cleanup удаляет ротированные копии сверх MaxBackups и старше MaxAge.
+func (r *RotatingFile) cleanup() error {
+	if r.cfg.MaxBackups <= 0 && r.cfg.MaxAge <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		path string
+		at   time.Time
+	}
+	prefix := r.path + "."
+	var backups []backup
+	for _, m := range matches {
+		at, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(m, prefix), time.Local)
+		if err != nil {
+			continue // чужой файл
+		}
+		backups = append(backups, backup{path: m, at: at})
+	}
+	// новые первыми
+	sort.Slice(backups, func(i, j int) bool { return backups[i].at.After(backups[j].at) })
+
+	var errs []string
+	for i, b := range backups {
+		expired := r.cfg.MaxAge > 0 && r.now().Sub(b.at) > r.cfg.MaxAge
+		extra := r.cfg.MaxBackups > 0 && i >= r.cfg.MaxBackups
+		if !expired && !extra {
+			continue
+		}
+		if err := os.Remove(b.path); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("remove old log files: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler возвращает slog.Handler, пишущий через этот логгер, чтобы сторонние
+// библиотеки на log/slog попадали в тот же вывод и формат.
+func (l *Logger) Handler() slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// Slog возвращает *slog.Logger поверх этого логгера.
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(l.Handler())
+}
+
+type slogHandler struct {
+	l      *Logger
+	groups string // префикс ключей из WithGroup, например "db."
+}
+
+func (h *slogHandler) Enabled(_ context.Context, lv slog.Level) bool {
+	return h.l.enabled(fromSlogLevel(lv))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	args := contextArgs(ctx)
+	r.Attrs(func(a slog.Attr) bool {
+		args = appendAttr(args, h.groups, a)
+		return true
+	})
+	fields := h.l.fields
+	if len(args) > 0 {
+		fields = append(append([]Field(nil), h.l.fields...), fieldsFromArgs(args)...)
+	}
+	h.l.log(fromSlogLevel(r.Level), r.Message, fields)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var args []any
+	for _, a := range attrs {
+		args = appendAttr(args, h.groups, a)
+	}
+	return &slogHandler{l: h.l.With(args...), groups: h.groups}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{l: h.l, groups: h.groups + name + "."}
+}
+
+// appendAttr разворачивает группы атрибутов в плоские ключи "group.key".
+func appendAttr(args []any, prefix string, a slog.Attr) []any {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			args = appendAttr(args, p, ga)
+		}
+		return args
+	}
+	if a.Equal(slog.Attr{}) {
+		return args
+	}
+	return append(args, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
+
+func fromSlogLevel(lv slog.Level) level {
+	switch {
+	case lv < slog.LevelInfo:
+		return DEBUG
+	case lv < slog.LevelWarn:
+		return INFO
+	case lv < slog.LevelError:
+		return WARN
+	default:
+		return ERROR
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+)
+
+// dialSyslog подключается к локальному syslog через unix сокет. Пустой путь -
+// системный сокет (/dev/log и аналоги).
+func dialSyslog(path, tag string) (io.WriteCloser, error) {
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	if path == "" {
+		return syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	}
+	w, err := syslog.Dial("unixgram", path, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		// rsyslog и journald слушают datagram сокет, syslog-ng может слушать stream
+		return syslog.Dial("unix", path, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	}
+	return w, nil
+}
@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+func dialSyslog(_, _ string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog output is not supported on this platform")
+}
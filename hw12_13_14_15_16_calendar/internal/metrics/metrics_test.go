@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrumentedStorageCountsSentinelErrors(t *testing.T) {
+	ctx := context.Background()
+	s := InstrumentStorage(memorystorage.New())
+
+	before := testutil.ToFloat64(storageErrors.WithLabelValues("get_event", "not_found"))
+	if _, err := s.GetEvent(ctx, "missing"); err == nil {
+		t.Fatal("expected not found error")
+	}
+	after := testutil.ToFloat64(storageErrors.WithLabelValues("get_event", "not_found"))
+	if after-before != 1 {
+		t.Fatalf("expected not_found counter to grow by 1, got %v", after-before)
+	}
+
+	e := storage.Event{ID: "1", Title: "meeting", At: time.Now()}
+	_ = s.CreateEvent(ctx, e)
+	before = testutil.ToFloat64(storageErrors.WithLabelValues("create_event", "date_busy"))
+	_ = s.CreateEvent(ctx, e)
+	after = testutil.ToFloat64(storageErrors.WithLabelValues("create_event", "date_busy"))
+	if after-before != 1 {
+		t.Fatalf("expected date_busy counter to grow by 1, got %v", after-before)
+	}
+}
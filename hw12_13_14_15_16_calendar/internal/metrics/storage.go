@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/app"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+// Storage - обёртка над app.Storage, измеряющая длительность и ошибки операций.
+type Storage struct {
+	next app.Storage
+}
+
+func InstrumentStorage(next app.Storage) *Storage {
+	return &Storage{next: next}
+}
+
+func (s *Storage) CreateEvent(ctx context.Context, e storage.Event) error {
+	start := time.Now()
+	err := s.next.CreateEvent(ctx, e)
+	ObserveStorage("create_event", err, time.Since(start))
+	return err
+}
+
+func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
+	start := time.Now()
+	err := s.next.UpdateEvent(ctx, e)
+	ObserveStorage("update_event", err, time.Since(start))
+	return err
+}
+
+func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
+	start := time.Now()
+	err := s.next.DeleteEvent(ctx, id)
+	ObserveStorage("delete_event", err, time.Since(start))
+	return err
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
+	start := time.Now()
+	e, err := s.next.GetEvent(ctx, id)
+	ObserveStorage("get_event", err, time.Since(start))
+	return e, err
+}
+
+func (s *Storage) ListEvents(ctx context.Context) ([]storage.Event, error) {
+	start := time.Now()
+	events, err := s.next.ListEvents(ctx)
+	ObserveStorage("list_events", err, time.Since(start))
+	return events, err
+}
+
+func (s *Storage) ListEventsDay(ctx context.Context, dayStart time.Time) ([]storage.Event, error) {
+	start := time.Now()
+	events, err := s.next.ListEventsDay(ctx, dayStart)
+	ObserveStorage("list_events_day", err, time.Since(start))
+	return events, err
+}
+
+func (s *Storage) ListEventsWeek(ctx context.Context, weekStart time.Time) ([]storage.Event, error) {
+	start := time.Now()
+	events, err := s.next.ListEventsWeek(ctx, weekStart)
+	ObserveStorage("list_events_week", err, time.Since(start))
+	return events, err
+}
+
+func (s *Storage) ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error) {
+	start := time.Now()
+	events, err := s.next.ListEventsMonth(ctx, monthStart)
+	ObserveStorage("list_events_month", err, time.Since(start))
+	return events, err
+}
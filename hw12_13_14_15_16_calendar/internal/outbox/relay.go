@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+	Debug(msg string)
+}
+
+// Storage - часть хранилища, которая нужна relay.
+type Storage interface {
+	// ProcessOutbox передаёт fn пачку недоставленных записей, которую не получат другие
+	// relay, пока fn не вернётся, и отмечает доставленными записи с возвращёнными ID.
+	ProcessOutbox(ctx context.Context, limit int,
+		fn func(ctx context.Context, records []storage.OutboxRecord) []int64) (int, error)
+	// PurgeOutbox удаляет записи, доставленные раньше before.
+	PurgeOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+const (
+	defaultRetention = 24 * time.Hour
+	maxPurgeInterval = time.Hour
+)
+
+// Relay переносит записи outbox в очередь. Доставка "как минимум один раз":
+// если процесс упадёт между публикацией и отметкой, запись будет опубликована повторно
+// с тем же ID сообщения. Несколько relay (по одному на экземпляр планировщика) делят
+// записи между собой. Доставленные записи хранятся retention и затем удаляются.
+type Relay struct {
+	logger    Logger
+	store     Storage
+	publisher queue.Publisher
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+}
+
+type Option func(*Relay)
+
+// WithRetention задаёт, сколько хранить доставленные записи (по умолчанию сутки).
+func WithRetention(d time.Duration) Option {
+	return func(r *Relay) {
+		if d > 0 {
+			r.retention = d
+		}
+	}
+}
+
+func NewRelay(logger Logger, store Storage, publisher queue.Publisher, interval time.Duration, batchSize int,
+	opts ...Option,
+) *Relay {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	r := &Relay{
+		logger:    logger,
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		retention: defaultRetention,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run периодически публикует накопившиеся записи до отмены контекста.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var lastPurge time.Time
+	for {
+		if time.Since(lastPurge) >= min(r.retention, maxPurgeInterval) {
+			if _, err := r.Purge(ctx); err != nil {
+				r.logger.Error("outbox relay: " + err.Error())
+			}
+			lastPurge = time.Now()
+		}
+		for {
+			n, err := r.Flush(ctx)
+			if err != nil {
+				r.logger.Error("outbox relay: " + err.Error())
+				break
+			}
+			// полная пачка - скорее всего, есть ещё записи
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush публикует одну пачку записей и возвращает количество доставленных.
+// Записи публикуются по порядку; на первой ошибке публикация останавливается,
+// чтобы не нарушить порядок изменений одного события.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	var publishErr error
+	n, err := r.store.ProcessOutbox(ctx, r.batchSize,
+		func(ctx context.Context, records []storage.OutboxRecord) []int64 {
+			delivered := make([]int64, 0, len(records))
+			for _, rec := range records {
+				msg, err := queue.EncodeEventChange(rec)
+				if err == nil {
+					err = r.publisher.Publish(ctx, msg)
+				}
+				if err != nil {
+					publishErr = fmt.Errorf("publish outbox record %d: %w", rec.ID, err)
+					break
+				}
+				delivered = append(delivered, rec.ID)
+			}
+			return delivered
+		})
+	if err != nil {
+		return 0, fmt.Errorf("process outbox: %w", err)
+	}
+	if n > 0 {
+		r.logger.Debug(fmt.Sprintf("outbox relay published %d records", n))
+	}
+	return n, publishErr
+}
+
+// Purge удаляет записи, доставленные раньше, чем retention назад.
+func (r *Relay) Purge(ctx context.Context) (int64, error) {
+	n, err := r.store.PurgeOutbox(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		return 0, fmt.Errorf("purge outbox: %w", err)
+	}
+	if n > 0 {
+		r.logger.Debug(fmt.Sprintf("outbox relay purged %d delivered records", n))
+	}
+	return n, nil
+}
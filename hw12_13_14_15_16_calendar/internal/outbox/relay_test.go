@@ -0,0 +1,125 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+)
+
+type fakePublisher struct {
+	msgs    []queue.Message
+	failAt  int // номер публикации (с 1), на которой вернуть ошибку
+	attempt int
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg queue.Message) error {
+	p.attempt++
+	if p.attempt == p.failAt {
+		return errors.New("queue is down")
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func TestRelayPublishesChangesInOrder(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New(memorystorage.WithOutbox())
+	at := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	e := storage.Event{ID: "1", Title: "meeting", At: at}
+	_ = store.CreateEvent(ctx, e)
+	e.Title = "moved meeting"
+	_ = store.UpdateEvent(ctx, e)
+	_ = store.DeleteEvent(ctx, e.ID)
+
+	pub := &fakePublisher{}
+	r := NewRelay(logger.New("error"), store, pub, time.Minute, 10)
+
+	n, err := r.Flush(ctx)
+	if err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 published records, got %d", n)
+	}
+
+	want := []string{storage.EventCreated, storage.EventUpdated, storage.EventDeleted}
+	for i, msg := range pub.msgs {
+		rec, err := queue.DecodeEventChange(msg)
+		if err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		if rec.Type != want[i] || rec.EventID != "1" {
+			t.Fatalf("record %d: expected %s for event 1, got %s for %s", i, want[i], rec.Type, rec.EventID)
+		}
+	}
+
+	// доставленные записи повторно не публикуются
+	if n, _ := r.Flush(ctx); n != 0 {
+		t.Fatalf("expected nothing to publish, got %d", n)
+	}
+}
+
+func TestRelayPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New(memorystorage.WithOutbox())
+	at := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	_ = store.CreateEvent(ctx, storage.Event{ID: "1", Title: "first", At: at})
+	_ = store.CreateEvent(ctx, storage.Event{ID: "2", Title: "second", At: at.Add(time.Hour)})
+
+	pub := &fakePublisher{failAt: 2}
+	r := NewRelay(logger.New("error"), store, pub, time.Minute, 10)
+
+	n, err := r.Flush(ctx)
+	if err == nil {
+		t.Fatal("expected error when publish fails")
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 delivered record before failure, got %d", n)
+	}
+
+	// после восстановления очереди публикуется только оставшаяся запись
+	n, err = r.Flush(ctx)
+	if err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if n != 1 || len(pub.msgs) != 2 {
+		t.Fatalf("expected remaining record to be published once, got n=%d total=%d", n, len(pub.msgs))
+	}
+	rec, _ := queue.DecodeEventChange(pub.msgs[1])
+	if rec.EventID != "2" {
+		t.Fatalf("expected event 2, got %s", rec.EventID)
+	}
+}
+
+type purgeRecorder struct {
+	Storage
+	before time.Time
+}
+
+func (p *purgeRecorder) PurgeOutbox(_ context.Context, before time.Time) (int64, error) {
+	p.before = before
+	return 3, nil
+}
+
+func TestRelayPurgesDeliveredRecords(t *testing.T) {
+	store := &purgeRecorder{Storage: memorystorage.New(memorystorage.WithOutbox())}
+	r := NewRelay(logger.New("error"), store, &fakePublisher{}, time.Minute, 10, WithRetention(time.Hour))
+
+	n, err := r.Purge(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 purged records, got %d, %v", n, err)
+	}
+	if d := time.Since(store.before); d < time.Hour || d > time.Hour+time.Minute {
+		t.Fatalf("expected records delivered over an hour ago to be purged, got cutoff %v ago", d)
+	}
+}
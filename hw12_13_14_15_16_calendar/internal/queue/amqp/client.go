@@ -0,0 +1,507 @@
+package amqpqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	errConnectionLost = errors.New("amqp: connection lost before publish confirm")
+	errNotConnected   = errors.New("amqp: not connected")
+)
+
+// Заголовки с метаданными повторной доставки.
+const (
+	headerAttempt   = "x-attempt"
+	headerLastError = "x-last-error"
+)
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+	Debug(msg string)
+}
+
+type Config struct {
+	URL            string
+	Exchange       string
+	ExchangeType   string
+	Queue          string
+	RoutingKey     string
+	Prefetch       int
+	ReconnectDelay time.Duration
+	Retry          queue.RetryPolicy
+}
+
+// Client - адаптер AMQP 0-9-1 (RabbitMQ), реализует queue.Publisher, queue.Consumer
+// и queue.DeadLetters. При старте объявляет exchange и очереди, публикует с подтверждениями
+// брокера, подтверждает полученные сообщения вручную и переподключается при обрыве соединения.
+//
+// Повторная доставка устроена через очередь <queue>.retry: сообщение публикуется туда с TTL,
+// равным задержке, и по истечении TTL брокер возвращает его в основной exchange.
+// Сообщения, исчерпавшие попытки, попадают в очередь <queue>.dead.
+type Client struct {
+	logger Logger
+	cfg    Config
+	dial   dialer
+
+	// mu защищает conn, ch и confirms. Состояние соединения и признак закрытия
+	// дублируются в атомарных полях, чтобы Ping не ждал mu, пока идёт переподключение.
+	mu       sync.Mutex
+	conn     connection
+	ch       channel
+	confirms chan amqp.Confirmation
+	// gen растёт с каждым подключением; по нему видно, жив ли канал, с которого пришло сообщение
+	gen  uint64
+	link atomic.Pointer[link]
+	shut atomic.Bool
+}
+
+// link - текущее соединение с брокером; nil в Client.link - соединения нет.
+type link struct {
+	closed chan *amqp.Error
+	lost   atomic.Bool
+}
+
+// broken сообщает, закрыто ли соединение. Уведомление из closed читается один раз,
+// поэтому результат запоминается в lost.
+func (l *link) broken() bool {
+	if l.lost.Load() {
+		return true
+	}
+	select {
+	case <-l.closed:
+		l.lost.Store(true)
+		return true
+	default:
+		return false
+	}
+}
+
+func New(logger Logger, cfg Config) *Client {
+	if cfg.ExchangeType == "" {
+		cfg.ExchangeType = amqp.ExchangeDirect
+	}
+	if cfg.RoutingKey == "" {
+		cfg.RoutingKey = cfg.Queue
+	}
+	if cfg.Prefetch <= 0 {
+		cfg.Prefetch = 1
+	}
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = time.Second
+	}
+	cfg.Retry = cfg.Retry.WithDefaults()
+	return &Client{
+		logger: logger,
+		cfg:    cfg,
+		dial:   dialAMQP,
+	}
+}
+
+// Connect подключается к брокеру и объявляет топологию, повторяя попытки до отмены контекста.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ensureLocked(ctx)
+}
+
+// Ping сообщает, есть ли живое соединение с брокером. Сам Ping не переподключается -
+// это делают Publish и Consume, - и не берёт mu, поэтому не ждёт переподключения
+// и его можно вызывать из проверки готовности.
+func (c *Client) Ping(_ context.Context) error {
+	if c.shut.Load() {
+		return queue.ErrClosed
+	}
+	if l := c.link.Load(); l == nil || l.broken() {
+		return errNotConnected
+	}
+	return nil
+}
+
+func (c *Client) Publish(ctx context.Context, msg queue.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureLocked(ctx); err != nil {
+		return err
+	}
+	return c.publishLocked(ctx, c.cfg.Exchange, c.cfg.RoutingKey, toPublishing(msg))
+}
+
+// publishLocked публикует сообщение и ждёт подтверждения брокера.
+func (c *Client) publishLocked(ctx context.Context, exchange, key string, p amqp.Publishing) error {
+	if err := c.ch.PublishWithContext(ctx, exchange, key, false, false, p); err != nil {
+		c.resetLocked()
+		return err
+	}
+
+	select {
+	case conf, ok := <-c.confirms:
+		if !ok {
+			c.resetLocked()
+			return errConnectionLost
+		}
+		if !conf.Ack {
+			return fmt.Errorf("amqp: message %s nacked by broker", p.MessageId)
+		}
+		return nil
+	case <-ctx.Done():
+		// подтверждение придёт позже и собьёт порядок, поэтому канал пересоздаём
+		c.resetLocked()
+		return ctx.Err()
+	}
+}
+
+func (c *Client) Consume(ctx context.Context, handler queue.Handler) error {
+	for {
+		deliveries, gen, err := c.subscribe(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, queue.ErrClosed):
+			return err
+		case err != nil:
+			// Qos или Consume не прошли - канал уже сброшен, подписываемся заново, как после обрыва
+			c.logger.Error("amqp: subscribe failed, retrying: " + err.Error())
+		default:
+			if done := c.consumeDeliveries(ctx, deliveries, gen, handler); done {
+				return nil
+			}
+			c.logger.Error("amqp: consumer connection lost, reconnecting")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.cfg.ReconnectDelay):
+		}
+	}
+}
+
+func (c *Client) Close() error {
+	c.shut.Store(true)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resetLocked()
+	return nil
+}
+
+// consumeDeliveries обрабатывает сообщения, пока канал доставки открыт. gen - поколение
+// соединения, на котором открыт deliveries. Возвращает true, если работа завершена отменой контекста.
+func (c *Client) consumeDeliveries(
+	ctx context.Context, deliveries <-chan amqp.Delivery, gen uint64, handler queue.Handler,
+) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case d, ok := <-deliveries:
+			if !ok {
+				return false
+			}
+			msg := fromDelivery(d)
+			if err := handler(ctx, msg); err != nil {
+				c.handleFailure(ctx, d, gen, msg, err)
+				continue
+			}
+			if err := d.Ack(false); err != nil {
+				c.logger.Error("amqp: ack failed: " + err.Error())
+			}
+		}
+	}
+}
+
+// handleFailure переносит сообщение в очередь повторов или недоставленных.
+// Исходное сообщение подтверждается только после успешной публикации копии.
+// Если канал, с которого пришло сообщение, уже закрыт, подтвердить его нельзя,
+// а брокер доставит его снова, поэтому копия не публикуется - иначе сообщение
+// обработалось бы дважды.
+func (c *Client) handleFailure(ctx context.Context, d amqp.Delivery, gen uint64, msg queue.Message, handlerErr error) {
+	msg.Attempt++
+	msg.LastError = handlerErr.Error()
+	p := toPublishing(msg)
+
+	c.mu.Lock()
+	if c.gen != gen || c.ch == nil || c.brokenLocked() {
+		c.mu.Unlock()
+		c.logger.Error(fmt.Sprintf("amqp: message %s failed, channel closed, left for redelivery: %v",
+			msg.ID, handlerErr))
+		return
+	}
+	var err error
+	if queue.IsPermanent(handlerErr) || c.cfg.Retry.Exhausted(msg.Attempt) {
+		c.logger.Error(fmt.Sprintf("amqp: message %s dead-lettered after %d attempts: %v",
+			msg.ID, msg.Attempt, handlerErr))
+		err = c.publishLocked(ctx, "", deadQueue(c.cfg), p)
+	} else {
+		backoff := c.cfg.Retry.Backoff(msg.Attempt)
+		c.logger.Error(fmt.Sprintf("amqp: message %s failed (attempt %d), retry in %v: %v",
+			msg.ID, msg.Attempt, backoff, handlerErr))
+		p.Expiration = strconv.FormatInt(backoff.Milliseconds(), 10)
+		err = c.publishLocked(ctx, "", retryQueue(c.cfg), p)
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		c.logger.Error("amqp: failed to schedule retry, message requeued: " + err.Error())
+		if err := d.Nack(false, true); err != nil {
+			c.logger.Error("amqp: nack failed: " + err.Error())
+		}
+		return
+	}
+	if err := d.Ack(false); err != nil {
+		c.logger.Error("amqp: ack failed: " + err.Error())
+	}
+}
+
+func (c *Client) DeadLetters(ctx context.Context, limit int) ([]queue.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureLocked(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		out  []queue.Message
+		held []amqp.Delivery
+	)
+	// сообщения забираются без подтверждения и затем возвращаются в очередь
+	defer func() {
+		for _, d := range held {
+			if err := d.Nack(false, true); err != nil {
+				c.logger.Error("amqp: nack failed: " + err.Error())
+			}
+		}
+	}()
+
+	for limit <= 0 || len(out) < limit {
+		d, ok, err := c.ch.Get(deadQueue(c.cfg), false)
+		if err != nil {
+			c.resetLocked()
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		held = append(held, d)
+		out = append(out, fromDelivery(d))
+	}
+	return out, nil
+}
+
+func (c *Client) Replay(ctx context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureLocked(ctx); err != nil {
+		return err
+	}
+
+	var held []amqp.Delivery
+	defer func() {
+		for _, d := range held {
+			if err := d.Nack(false, true); err != nil {
+				c.logger.Error("amqp: nack failed: " + err.Error())
+			}
+		}
+	}()
+
+	for {
+		d, ok, err := c.ch.Get(deadQueue(c.cfg), false)
+		if err != nil {
+			c.resetLocked()
+			return err
+		}
+		if !ok {
+			return queue.ErrMessageNotFound
+		}
+		if d.MessageId != id {
+			held = append(held, d)
+			continue
+		}
+
+		msg := fromDelivery(d)
+		msg.Attempt = 0
+		msg.LastError = ""
+		if err := c.publishLocked(ctx, c.cfg.Exchange, c.cfg.RoutingKey, toPublishing(msg)); err != nil {
+			held = append(held, d)
+			return err
+		}
+		return d.Ack(false)
+	}
+}
+
+// subscribe начинает потребление и возвращает поколение соединения, на котором открыта подписка.
+func (c *Client) subscribe(ctx context.Context) (<-chan amqp.Delivery, uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureLocked(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := c.ch.Qos(c.cfg.Prefetch, 0, false); err != nil {
+		c.resetLocked()
+		return nil, 0, err
+	}
+	deliveries, err := c.ch.Consume(c.cfg.Queue, "", false, false, false, false, nil)
+	if err != nil {
+		c.resetLocked()
+		return nil, 0, err
+	}
+	return deliveries, c.gen, nil
+}
+
+// ensureLocked проверяет соединение и при необходимости переподключается.
+// На время паузы между попытками mu отпускается, чтобы не блокировать Close
+// и остальных пользователей клиента; после паузы состояние проверяется заново -
+// за это время соединение мог восстановить кто-то другой.
+func (c *Client) ensureLocked(ctx context.Context) error {
+	for {
+		if c.shut.Load() {
+			return queue.ErrClosed
+		}
+		if c.ch != nil && !c.brokenLocked() {
+			return nil
+		}
+		c.resetLocked()
+
+		err := c.connectLocked()
+		if err == nil {
+			c.logger.Info("amqp: connected, exchange=" + c.cfg.Exchange + " queue=" + c.cfg.Queue)
+			return nil
+		}
+		c.logger.Error("amqp: connect failed: " + err.Error())
+
+		c.mu.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.cfg.ReconnectDelay):
+		}
+		c.mu.Lock()
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) connectLocked() error {
+	conn, err := c.dial(c.cfg.URL)
+	if err != nil {
+		return err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if err := declareTopology(ch, c.cfg); err != nil {
+		_ = ch.Close()
+		_ = conn.Close()
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		_ = conn.Close()
+		return err
+	}
+
+	c.conn = conn
+	c.ch = ch
+	c.gen++
+	c.confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	c.link.Store(&link{closed: conn.NotifyClose(make(chan *amqp.Error, 1))})
+	return nil
+}
+
+func declareTopology(ch channel, cfg Config) error {
+	if err := ch.ExchangeDeclare(cfg.Exchange, cfg.ExchangeType, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange %s: %w", cfg.Exchange, err)
+	}
+	if _, err := ch.QueueDeclare(cfg.Queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue %s: %w", cfg.Queue, err)
+	}
+	if err := ch.QueueBind(cfg.Queue, cfg.RoutingKey, cfg.Exchange, false, nil); err != nil {
+		return fmt.Errorf("bind queue %s: %w", cfg.Queue, err)
+	}
+	// просроченные сообщения из очереди повторов возвращаются в основной exchange
+	retryArgs := amqp.Table{
+		"x-dead-letter-exchange":    cfg.Exchange,
+		"x-dead-letter-routing-key": cfg.RoutingKey,
+	}
+	if _, err := ch.QueueDeclare(retryQueue(cfg), true, false, false, false, retryArgs); err != nil {
+		return fmt.Errorf("declare queue %s: %w", retryQueue(cfg), err)
+	}
+	if _, err := ch.QueueDeclare(deadQueue(cfg), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue %s: %w", deadQueue(cfg), err)
+	}
+	return nil
+}
+
+func retryQueue(cfg Config) string {
+	return cfg.Queue + ".retry"
+}
+
+func deadQueue(cfg Config) string {
+	return cfg.Queue + ".dead"
+}
+
+func toPublishing(msg queue.Message) amqp.Publishing {
+	p := amqp.Publishing{
+		MessageId:    msg.ID,
+		ContentType:  msg.ContentType,
+		Body:         msg.Body,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+	}
+	if msg.Attempt > 0 {
+		p.Headers = amqp.Table{
+			headerAttempt:   int32(msg.Attempt), //nolint:gosec
+			headerLastError: msg.LastError,
+		}
+	}
+	return p
+}
+
+func fromDelivery(d amqp.Delivery) queue.Message {
+	msg := queue.Message{ID: d.MessageId, ContentType: d.ContentType, Body: d.Body}
+	switch v := d.Headers[headerAttempt].(type) {
+	case int32:
+		msg.Attempt = int(v)
+	case int64:
+		msg.Attempt = int(v)
+	case int:
+		msg.Attempt = v
+	}
+	if v, ok := d.Headers[headerLastError].(string); ok {
+		msg.LastError = v
+	}
+	return msg
+}
+
+func (c *Client) brokenLocked() bool {
+	l := c.link.Load()
+	return l == nil || l.broken()
+}
+
+func (c *Client) resetLocked() {
+	if c.ch != nil {
+		_ = c.ch.Close()
+	}
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	c.ch = nil
+	c.conn = nil
+	c.confirms = nil
+	c.link.Store(nil)
+}
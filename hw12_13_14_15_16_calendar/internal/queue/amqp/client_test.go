@@ -0,0 +1,629 @@
+package amqpqueue
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// fakeBroker - брокер в памяти, заменяющий RabbitMQ в тестах.
+type fakeBroker struct {
+	mu        sync.Mutex
+	exchanges map[string]string
+	bindings  map[string]string // exchange/key -> queue
+	queues    map[string][]amqp.Publishing
+	queueArgs map[string]amqp.Table
+	conns     []*fakeConn
+	dials     int
+	failDials int
+	// failConsumes - сколько следующих вызовов Consume на канале вернут ошибку
+	failConsumes int
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{
+		exchanges: make(map[string]string),
+		bindings:  make(map[string]string),
+		queues:    make(map[string][]amqp.Publishing),
+		queueArgs: make(map[string]amqp.Table),
+	}
+}
+
+func (b *fakeBroker) dial(_ string) (connection, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dials++
+	if b.failDials > 0 {
+		b.failDials--
+		return nil, errors.New("connection refused")
+	}
+	c := &fakeConn{broker: b, closed: make(chan struct{})}
+	b.conns = append(b.conns, c)
+	return c, nil
+}
+
+// dropConnections имитирует падение брокера.
+func (b *fakeBroker) dropConnections() {
+	b.mu.Lock()
+	conns := b.conns
+	b.conns = nil
+	b.mu.Unlock()
+	for _, c := range conns {
+		_ = c.Close()
+	}
+}
+
+// route находит очередь по exchange и ключу; пустой exchange маршрутизирует по имени очереди.
+func (b *fakeBroker) route(exchange, key string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if exchange == "" {
+		_, ok := b.queues[key]
+		return key, ok
+	}
+	q, ok := b.bindings[exchange+"/"+key]
+	return q, ok
+}
+
+func (b *fakeBroker) push(queueName string, p amqp.Publishing) {
+	b.mu.Lock()
+	args := b.queueArgs[queueName]
+	b.mu.Unlock()
+
+	// очередь с dead-letter exchange и TTL сообщения - как очередь повторов в RabbitMQ
+	if dlx, ok := args["x-dead-letter-exchange"].(string); ok && p.Expiration != "" {
+		key, _ := args["x-dead-letter-routing-key"].(string)
+		ttl, _ := strconv.Atoi(p.Expiration)
+		p.Expiration = ""
+		time.AfterFunc(time.Duration(ttl)*time.Millisecond, func() {
+			if target, ok := b.route(dlx, key); ok {
+				b.push(target, p)
+			}
+		})
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queues[queueName] = append(b.queues[queueName], p)
+}
+
+func (b *fakeBroker) pop(queueName string) (amqp.Publishing, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q := b.queues[queueName]
+	if len(q) == 0 {
+		return amqp.Publishing{}, false
+	}
+	b.queues[queueName] = q[1:]
+	return q[0], true
+}
+
+func (b *fakeBroker) depth(queueName string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queues[queueName])
+}
+
+type fakeConn struct {
+	broker    *fakeBroker
+	mu        sync.Mutex
+	closed    chan struct{}
+	isClosed  bool
+	notifiers []chan *amqp.Error
+}
+
+func (c *fakeConn) Channel() (channel, error) {
+	return &fakeChannel{conn: c}, nil
+}
+
+func (c *fakeConn) NotifyClose(receiver chan *amqp.Error) chan *amqp.Error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notifiers = append(c.notifiers, receiver)
+	return receiver
+}
+
+func (c *fakeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.isClosed {
+		return nil
+	}
+	c.isClosed = true
+	close(c.closed)
+	for _, n := range c.notifiers {
+		close(n)
+	}
+	return nil
+}
+
+type fakeChannel struct {
+	conn     *fakeConn
+	confirms chan amqp.Confirmation
+	tag      uint64
+}
+
+func (ch *fakeChannel) ExchangeDeclare(name, kind string, _, _, _, _ bool, _ amqp.Table) error {
+	b := ch.conn.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.exchanges[name] = kind
+	return nil
+}
+
+func (ch *fakeChannel) QueueDeclare(name string, _, _, _, _ bool, args amqp.Table) (amqp.Queue, error) {
+	b := ch.conn.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.queues[name]; !ok {
+		b.queues[name] = nil
+	}
+	b.queueArgs[name] = args
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *fakeChannel) QueueBind(name, key, exchange string, _ bool, _ amqp.Table) error {
+	b := ch.conn.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bindings[exchange+"/"+key] = name
+	return nil
+}
+
+func (ch *fakeChannel) Confirm(_ bool) error { return nil }
+
+func (ch *fakeChannel) NotifyPublish(confirm chan amqp.Confirmation) chan amqp.Confirmation {
+	ch.confirms = confirm
+	return confirm
+}
+
+func (ch *fakeChannel) PublishWithContext(_ context.Context, exchange, key string, _, _ bool,
+	msg amqp.Publishing,
+) error {
+	select {
+	case <-ch.conn.closed:
+		return amqp.ErrClosed
+	default:
+	}
+	b := ch.conn.broker
+	queueName, ok := b.route(exchange, key)
+	if ok {
+		b.push(queueName, msg)
+	}
+	ch.tag++
+	ch.confirms <- amqp.Confirmation{DeliveryTag: ch.tag, Ack: ok}
+	return nil
+}
+
+func (ch *fakeChannel) Qos(_, _ int, _ bool) error { return nil }
+
+func (ch *fakeChannel) Consume(queueName, _ string, _, _, _, _ bool, _ amqp.Table) (<-chan amqp.Delivery, error) {
+	b := ch.conn.broker
+	b.mu.Lock()
+	if b.failConsumes > 0 {
+		b.failConsumes--
+		b.mu.Unlock()
+		return nil, errors.New("channel closed")
+	}
+	b.mu.Unlock()
+
+	out := make(chan amqp.Delivery)
+	go func() {
+		defer close(out)
+		for {
+			p, ok := ch.conn.broker.pop(queueName)
+			if !ok {
+				select {
+				case <-ch.conn.closed:
+					return
+				case <-time.After(time.Millisecond):
+					continue
+				}
+			}
+			d := fakeDelivery(ch.conn.broker, queueName, p)
+			select {
+			case out <- d:
+			case <-ch.conn.closed:
+				ch.conn.broker.push(queueName, p)
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (ch *fakeChannel) Get(queueName string, _ bool) (amqp.Delivery, bool, error) {
+	p, ok := ch.conn.broker.pop(queueName)
+	if !ok {
+		return amqp.Delivery{}, false, nil
+	}
+	return fakeDelivery(ch.conn.broker, queueName, p), true, nil
+}
+
+func (ch *fakeChannel) Close() error { return nil }
+
+func fakeDelivery(b *fakeBroker, queueName string, p amqp.Publishing) amqp.Delivery {
+	return amqp.Delivery{
+		Acknowledger: &fakeAck{broker: b, queue: queueName, msg: p},
+		Headers:      p.Headers,
+		MessageId:    p.MessageId,
+		ContentType:  p.ContentType,
+		Body:         p.Body,
+	}
+}
+
+type fakeAck struct {
+	broker *fakeBroker
+	queue  string
+	msg    amqp.Publishing
+}
+
+func (a *fakeAck) Ack(_ uint64, _ bool) error { return nil }
+
+func (a *fakeAck) Nack(_ uint64, _ bool, requeue bool) error {
+	if requeue {
+		a.broker.push(a.queue, a.msg)
+	}
+	return nil
+}
+
+func (a *fakeAck) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+func newTestClient(b *fakeBroker) *Client {
+	c := New(logger.New("error"), Config{
+		Exchange:       "calendar",
+		Queue:          "notifications",
+		ReconnectDelay: time.Millisecond,
+		Retry: queue.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+		},
+	})
+	c.dial = b.dial
+	return c
+}
+
+func TestClientDeclaresTopology(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	defer c.Close()
+
+	if err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	if b.exchanges["calendar"] != amqp.ExchangeDirect {
+		t.Fatalf("expected direct exchange to be declared, got %v", b.exchanges)
+	}
+	if b.bindings["calendar/notifications"] != "notifications" {
+		t.Fatalf("expected queue to be bound, got %v", b.bindings)
+	}
+}
+
+func TestClientPublishConsume(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.Publish(ctx, queue.Message{ID: "1", Body: []byte("hello")}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	attempts := 0
+	got := make(chan queue.Message, 1)
+	go func() {
+		_ = c.Consume(ctx, func(_ context.Context, msg queue.Message) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("temporary failure")
+			}
+			got <- msg
+			return nil
+		})
+	}()
+
+	select {
+	case msg := <-got:
+		if string(msg.Body) != "hello" || msg.ID != "1" {
+			t.Fatalf("unexpected message: %+v", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("message was not consumed")
+	}
+	cancel()
+
+	if attempts != 2 {
+		t.Fatalf("expected failed message to be redelivered, attempts=%d", attempts)
+	}
+	if b.depth("notifications") != 0 {
+		t.Fatalf("expected queue to be empty, got %d", b.depth("notifications"))
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	b := newFakeBroker()
+	b.failDials = 2
+	c := newTestClient(b)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.Publish(ctx, queue.Message{ID: "1", Body: []byte("first")}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	if b.dials != 3 {
+		t.Fatalf("expected 3 dial attempts, got %d", b.dials)
+	}
+
+	b.dropConnections()
+
+	if err := c.Publish(ctx, queue.Message{ID: "2", Body: []byte("second")}); err != nil {
+		t.Fatalf("publish after reconnect failed: %v", err)
+	}
+	if b.depth("notifications") != 2 {
+		t.Fatalf("expected 2 messages in queue, got %d", b.depth("notifications"))
+	}
+
+	var mu sync.Mutex
+	var received []string
+	done := make(chan struct{})
+	go func() {
+		_ = c.Consume(ctx, func(_ context.Context, msg queue.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, msg.ID)
+			if len(received) == 1 {
+				// обрыв соединения посреди потребления
+				go b.dropConnections()
+			}
+			if len(received) == 2 {
+				close(done)
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("consumer did not recover after connection loss")
+	}
+}
+
+func TestClientConsumeRetriesSubscribe(t *testing.T) {
+	b := newFakeBroker()
+	b.failConsumes = 2
+	c := newTestClient(b)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.Publish(ctx, queue.Message{ID: "1", Body: []byte("x")}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+
+	received := make(chan string, 1)
+	consumed := make(chan error, 1)
+	go func() {
+		consumed <- c.Consume(ctx, func(_ context.Context, msg queue.Message) error {
+			received <- msg.ID
+			return nil
+		})
+	}()
+
+	select {
+	case id := <-received:
+		if id != "1" {
+			t.Fatalf("unexpected message %q", id)
+		}
+	case err := <-consumed:
+		t.Fatalf("consumer stopped after subscribe failure: %v", err)
+	case <-ctx.Done():
+		t.Fatal("consumer did not recover after subscribe failure")
+	}
+	if b.dials < 3 {
+		t.Fatalf("expected reconnect after each failed subscribe, got %d dials", b.dials)
+	}
+}
+
+func TestClientFailureOnStaleChannel(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := c.Publish(ctx, queue.Message{ID: "1", Body: []byte("x")}); err != nil {
+		t.Fatalf("publish failed: %v", err)
+	}
+	deliveries, gen, err := c.subscribe(ctx)
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	d := <-deliveries
+
+	// пока сообщение обрабатывалось, соединение оборвалось и было восстановлено
+	b.dropConnections()
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("reconnect failed: %v", err)
+	}
+	c.handleFailure(ctx, d, gen, fromDelivery(d), queue.Permanent(errors.New("boom")))
+
+	// копия не публикуется: неподтверждённое сообщение брокер доставит снова сам
+	if n := b.depth("notifications.dead"); n != 0 {
+		t.Fatalf("expected no dead-lettered copy, got %d", n)
+	}
+	if n := b.depth("notifications"); n != 0 {
+		t.Fatalf("expected message not to be requeued by client, got %d", n)
+	}
+}
+
+func TestClientClosed(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	_ = c.Close()
+
+	err := c.Publish(context.Background(), queue.Message{ID: "1"})
+	if !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestClientPing(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err == nil {
+		t.Fatal("expected ping to fail before connect")
+	}
+	if err := c.Connect(ctx); err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("ping failed: %v", err)
+	}
+
+	b.dropConnections()
+	if err := c.Ping(ctx); err == nil {
+		t.Fatal("expected ping to fail after connection loss")
+	}
+
+	_ = c.Close()
+	if err := c.Ping(ctx); !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestClientPingDuringReconnect(t *testing.T) {
+	b := newFakeBroker()
+	b.failDials = 1 << 30
+	c := newTestClient(b)
+	c.cfg.ReconnectDelay = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	published := make(chan error, 1)
+	go func() {
+		published <- c.Publish(ctx, queue.Message{ID: "1"})
+	}()
+	// ждём первой неудачной попытки подключения
+	for {
+		b.mu.Lock()
+		dials := b.dials
+		b.mu.Unlock()
+		if dials > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	pinged := make(chan error, 1)
+	go func() { pinged <- c.Ping(context.Background()) }()
+	select {
+	case err := <-pinged:
+		if err == nil {
+			t.Fatal("expected ping to fail while broker is down")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ping blocked while publish was reconnecting")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		_ = c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close blocked while publish was reconnecting")
+	}
+	if err := c.Ping(context.Background()); !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	cancel()
+	if err := <-published; err == nil {
+		t.Fatal("expected publish to fail")
+	}
+}
+
+func TestClientDeadLetterAndReplay(t *testing.T) {
+	b := newFakeBroker()
+	c := newTestClient(b)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = c.Publish(ctx, queue.Message{ID: "poison", Body: []byte("x")})
+	_ = c.Publish(ctx, queue.Message{ID: "broken", Body: []byte("y")})
+
+	var mu sync.Mutex
+	attempts := map[string][]int{}
+	consumeCtx, stopConsume := context.WithCancel(ctx)
+	go func() {
+		_ = c.Consume(consumeCtx, func(_ context.Context, msg queue.Message) error {
+			mu.Lock()
+			attempts[msg.ID] = append(attempts[msg.ID], msg.Attempt)
+			mu.Unlock()
+			if msg.ID == "broken" {
+				return queue.Permanent(errors.New("cannot decode"))
+			}
+			return errors.New("always fails")
+		})
+	}()
+
+	for b.depth("notifications.dead") < 2 && ctx.Err() == nil {
+		time.Sleep(5 * time.Millisecond)
+	}
+	stopConsume()
+
+	mu.Lock()
+	if got := attempts["poison"]; len(got) != 3 || got[2] != 2 {
+		t.Fatalf("expected 3 attempts with growing counter for poison message, got %v", got)
+	}
+	if got := attempts["broken"]; len(got) != 1 {
+		t.Fatalf("permanent error must not be retried, got %v", got)
+	}
+	mu.Unlock()
+
+	dead, err := c.DeadLetters(ctx, 10)
+	if err != nil {
+		t.Fatalf("list dead letters failed: %v", err)
+	}
+	if len(dead) != 2 {
+		t.Fatalf("expected 2 dead letters, got %d", len(dead))
+	}
+	for _, msg := range dead {
+		if msg.LastError == "" || msg.Attempt == 0 {
+			t.Fatalf("expected failure metadata on dead letter, got %+v", msg)
+		}
+	}
+	// просмотр не должен забирать сообщения из очереди
+	if b.depth("notifications.dead") != 2 {
+		t.Fatalf("expected dead letters to stay in queue, got %d", b.depth("notifications.dead"))
+	}
+
+	if err := c.Replay(ctx, "poison"); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if b.depth("notifications") != 1 || b.depth("notifications.dead") != 1 {
+		t.Fatalf("expected message moved back to main queue, main=%d dead=%d",
+			b.depth("notifications"), b.depth("notifications.dead"))
+	}
+	if err := c.Replay(ctx, "missing"); !errors.Is(err, queue.ErrMessageNotFound) {
+		t.Fatalf("expected ErrMessageNotFound, got %v", err)
+	}
+}
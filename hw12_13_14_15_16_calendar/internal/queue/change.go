@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+type eventChangeJSON struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	EventID   string          `json:"event_id"`
+	CreatedAt string          `json:"created_at"` // RFC3339 format
+	Event     json.RawMessage `json:"event"`
+}
+
+// EncodeEventChange сериализует запись outbox в сообщение очереди. ID сообщения
+// стабилен для записи, поэтому потребители могут отбрасывать повторы.
+func EncodeEventChange(rec storage.OutboxRecord) (Message, error) {
+	body, err := json.Marshal(eventChangeJSON{
+		ID:        rec.ID,
+		Type:      rec.Type,
+		EventID:   rec.EventID,
+		CreatedAt: rec.CreatedAt.Format(time.RFC3339Nano),
+		Event:     rec.Payload,
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{ID: "outbox-" + strconv.FormatInt(rec.ID, 10), ContentType: contentTypeJSON, Body: body}, nil
+}
+
+// DecodeEventChange восстанавливает запись outbox из сообщения очереди. Потребителя
+// очереди изменений в этом репозитории пока нет - функция для внешних сервисов и тестов.
+func DecodeEventChange(msg Message) (storage.OutboxRecord, error) {
+	var v eventChangeJSON
+	if err := json.Unmarshal(msg.Body, &v); err != nil {
+		return storage.OutboxRecord{}, err
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, v.CreatedAt)
+	if err != nil {
+		return storage.OutboxRecord{}, err
+	}
+	return storage.OutboxRecord{
+		ID:        v.ID,
+		Type:      v.Type,
+		EventID:   v.EventID,
+		Payload:   v.Event,
+		CreatedAt: createdAt,
+	}, nil
+}
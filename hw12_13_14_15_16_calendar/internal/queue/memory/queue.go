@@ -0,0 +1,153 @@
+package memoryqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+)
+
+// Queue - очередь в памяти процесса, реализует queue.Publisher, queue.Consumer
+// и queue.DeadLetters. Используется в тестах и при запуске без брокера.
+type Queue struct {
+	policy   queue.RetryPolicy
+	mu       sync.Mutex
+	messages []queue.Message
+	dead     []queue.Message
+	notify   chan struct{}
+	done     chan struct{}
+	closed   bool
+}
+
+func New(policy queue.RetryPolicy) *Queue {
+	return &Queue{
+		policy: policy.WithDefaults(),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+func (q *Queue) Publish(_ context.Context, msg queue.Message) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return queue.ErrClosed
+	}
+	q.messages = append(q.messages, msg)
+	q.mu.Unlock()
+
+	q.signal()
+	return nil
+}
+
+func (q *Queue) Consume(ctx context.Context, handler queue.Handler) error {
+	for {
+		msg, ok := q.pop()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-q.done:
+				return queue.ErrClosed
+			case <-q.notify:
+				continue
+			}
+		}
+
+		if err := handler(ctx, msg); err != nil {
+			q.fail(msg, err)
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// Len возвращает количество сообщений, ожидающих обработки.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.messages)
+}
+
+func (q *Queue) DeadLetters(_ context.Context, limit int) ([]queue.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := len(q.dead)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	return append([]queue.Message(nil), q.dead[:n]...), nil
+}
+
+func (q *Queue) Replay(_ context.Context, id string) error {
+	q.mu.Lock()
+	for i, msg := range q.dead {
+		if msg.ID != id {
+			continue
+		}
+		q.dead = append(q.dead[:i], q.dead[i+1:]...)
+		msg.Attempt = 0
+		msg.LastError = ""
+		q.messages = append(q.messages, msg)
+		q.mu.Unlock()
+		q.signal()
+		return nil
+	}
+	q.mu.Unlock()
+	return queue.ErrMessageNotFound
+}
+
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.done)
+	}
+	return nil
+}
+
+func (q *Queue) pop() (queue.Message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.messages) == 0 {
+		return queue.Message{}, false
+	}
+	msg := q.messages[0]
+	q.messages = q.messages[1:]
+	return msg, true
+}
+
+// fail планирует повторную доставку сообщения или переносит его в недоставленные.
+func (q *Queue) fail(msg queue.Message, err error) {
+	msg.Attempt++
+	msg.LastError = err.Error()
+
+	if queue.IsPermanent(err) || q.policy.Exhausted(msg.Attempt) {
+		q.mu.Lock()
+		q.dead = append(q.dead, msg)
+		q.mu.Unlock()
+		return
+	}
+
+	time.AfterFunc(q.policy.Backoff(msg.Attempt), func() {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		q.messages = append(q.messages, msg)
+		q.mu.Unlock()
+		q.signal()
+	})
+}
+
+func (q *Queue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
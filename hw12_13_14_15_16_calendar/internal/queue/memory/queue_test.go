@@ -0,0 +1,117 @@
+package memoryqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+)
+
+var testPolicy = queue.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     10 * time.Millisecond,
+	Multiplier:     2,
+}
+
+func TestQueuePublishConsume(t *testing.T) {
+	q := New(testPolicy)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, id := range []string{"1", "2", "3"} {
+		if err := q.Publish(ctx, queue.Message{ID: id}); err != nil {
+			t.Fatalf("publish failed: %v", err)
+		}
+	}
+
+	var got []queue.Message
+	failed := false
+	_ = q.Consume(ctx, func(_ context.Context, msg queue.Message) error {
+		// первое сообщение с ошибкой должно быть доставлено повторно
+		if msg.ID == "1" && !failed {
+			failed = true
+			return errors.New("temporary failure")
+		}
+		got = append(got, msg)
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+
+	if len(got) != 3 || got[2].ID != "1" {
+		t.Fatalf("expected all messages with retried one last, got %v", got)
+	}
+	if got[2].Attempt != 1 || got[2].LastError != "temporary failure" {
+		t.Fatalf("expected retry metadata, got %+v", got[2])
+	}
+	if q.Len() != 0 {
+		t.Fatalf("expected empty queue, got %d", q.Len())
+	}
+}
+
+func TestQueueDeadLetterAndReplay(t *testing.T) {
+	q := New(testPolicy)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = q.Publish(ctx, queue.Message{ID: "poison"})
+	_ = q.Publish(ctx, queue.Message{ID: "broken"})
+
+	go func() {
+		_ = q.Consume(ctx, func(_ context.Context, msg queue.Message) error {
+			if msg.ID == "broken" {
+				return queue.Permanent(errors.New("cannot decode"))
+			}
+			return errors.New("always fails")
+		})
+	}()
+
+	var dead []queue.Message
+	for len(dead) < 2 && ctx.Err() == nil {
+		time.Sleep(5 * time.Millisecond)
+		dead, _ = q.DeadLetters(ctx, 10)
+	}
+	cancel()
+
+	if len(dead) != 2 {
+		t.Fatalf("expected 2 dead letters, got %d", len(dead))
+	}
+	for _, msg := range dead {
+		switch msg.ID {
+		case "poison":
+			if msg.Attempt != testPolicy.MaxAttempts {
+				t.Fatalf("expected %d attempts for poison message, got %d", testPolicy.MaxAttempts, msg.Attempt)
+			}
+		case "broken":
+			if msg.Attempt != 1 {
+				t.Fatalf("permanent error must not be retried, got %d attempts", msg.Attempt)
+			}
+		}
+	}
+
+	if err := q.Replay(context.Background(), "poison"); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected replayed message in queue, got %d", q.Len())
+	}
+	if err := q.Replay(context.Background(), "missing"); !errors.Is(err, queue.ErrMessageNotFound) {
+		t.Fatalf("expected ErrMessageNotFound, got %v", err)
+	}
+}
+
+func TestQueueClosed(t *testing.T) {
+	q := New(testPolicy)
+	_ = q.Close()
+
+	if err := q.Publish(context.Background(), queue.Message{ID: "1"}); !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := q.Consume(context.Background(), func(context.Context, queue.Message) error { return nil }); !errors.Is(err, queue.ErrClosed) {
+		t.Fatalf("expected ErrClosed from consume, got %v", err)
+	}
+}
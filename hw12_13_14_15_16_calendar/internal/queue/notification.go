@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+const contentTypeJSON = "application/json"
+
+type notificationJSON struct {
+	EventID string `json:"event_id"`
+	Title   string `json:"title"`
+	At      string `json:"at"` // RFC3339 format
+	UserID  string `json:"user_id"`
+	Offset  string `json:"offset"` // Go duration format
+	Channel string `json:"channel"`
+}
+
+// EncodeNotification сериализует уведомление в сообщение очереди.
+func EncodeNotification(n storage.Notification) (Message, error) {
+	body, err := json.Marshal(notificationJSON{
+		EventID: n.EventID,
+		Title:   n.Title,
+		At:      n.At.Format(time.RFC3339Nano),
+		UserID:  n.UserID,
+		Offset:  n.Offset.String(),
+		Channel: n.Channel,
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{ID: n.Key(), ContentType: contentTypeJSON, Body: body}, nil
+}
+
+// DecodeNotification восстанавливает уведомление из сообщения очереди.
+func DecodeNotification(msg Message) (storage.Notification, error) {
+	var v notificationJSON
+	if err := json.Unmarshal(msg.Body, &v); err != nil {
+		return storage.Notification{}, err
+	}
+	at, err := time.Parse(time.RFC3339Nano, v.At)
+	if err != nil {
+		return storage.Notification{}, err
+	}
+	offset, err := time.ParseDuration(v.Offset)
+	if err != nil {
+		return storage.Notification{}, err
+	}
+	return storage.Notification{
+		EventID: v.EventID,
+		Title:   v.Title,
+		At:      at,
+		UserID:  v.UserID,
+		Offset:  offset,
+		Channel: v.Channel,
+	}, nil
+}
+
+// NotificationPublisher публикует уведомления планировщика в очередь.
+type NotificationPublisher struct {
+	pub Publisher
+}
+
+func NewNotificationPublisher(pub Publisher) *NotificationPublisher {
+	return &NotificationPublisher{pub: pub}
+}
+
+func (p *NotificationPublisher) Publish(ctx context.Context, n storage.Notification) error {
+	msg, err := EncodeNotification(n)
+	if err != nil {
+		return err
+	}
+	return p.pub.Publish(ctx, msg)
+}
@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+func TestNotificationRoundTrip(t *testing.T) {
+	n := storage.Notification{
+		EventID: "1",
+		Title:   "meeting",
+		At:      time.Date(2025, 1, 10, 12, 0, 0, 500, time.UTC),
+		UserID:  "user1",
+		Offset:  15 * time.Minute,
+		Channel: storage.ChannelEmail,
+	}
+
+	msg, err := EncodeNotification(n)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	if msg.ID != n.Key() {
+		t.Fatalf("expected message id to be notification key, got %s", msg.ID)
+	}
+
+	got, err := DecodeNotification(msg)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if got.Key() != n.Key() || got.Title != n.Title || got.UserID != n.UserID {
+		t.Fatalf("mismatch got=%+v want=%+v", got, n)
+	}
+}
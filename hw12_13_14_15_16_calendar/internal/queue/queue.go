@@ -0,0 +1,36 @@
+package queue
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClosed возвращается при работе с закрытой очередью.
+var ErrClosed = errors.New("queue closed")
+
+// Message - сообщение в очереди, не зависящее от конкретного брокера.
+type Message struct {
+	ID          string
+	ContentType string
+	Body        []byte
+	// Attempt - количество уже неудавшихся попыток обработки.
+	Attempt int
+	// LastError - ошибка последней попытки обработки.
+	LastError string
+}
+
+// Handler обрабатывает сообщение. Если обработчик вернул ошибку, сообщение
+// доставляется повторно согласно RetryPolicy, а после исчерпания попыток
+// (или сразу для ошибки Permanent) попадает в очередь недоставленных.
+type Handler func(ctx context.Context, msg Message) error
+
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+type Consumer interface {
+	// Consume читает сообщения до отмены контекста.
+	Consume(ctx context.Context, handler Handler) error
+	Close() error
+}
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMessageNotFound возвращается, если сообщения нет в очереди недоставленных.
+var ErrMessageNotFound = errors.New("message not found")
+
+// RetryPolicy описывает повторную доставку сообщений, которые не удалось обработать:
+// задержка растёт экспоненциально, после MaxAttempts попыток сообщение уходит
+// в очередь недоставленных (dead-letter).
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Multiplier:     2,
+	}
+}
+
+// WithDefaults заполняет незаданные поля значениями по умолчанию.
+func (p RetryPolicy) WithDefaults() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = def.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = def.Multiplier
+	}
+	return p
+}
+
+// Backoff возвращает задержку перед попыткой attempt (начиная с 1).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		d *= p.Multiplier
+		if d >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+	return time.Duration(d)
+}
+
+// Exhausted сообщает, что после attempt неудачных попыток сообщение больше не повторяется.
+func (p RetryPolicy) Exhausted(attempt int) bool {
+	return attempt >= p.MaxAttempts
+}
+
+// DeadLetters - доступ к сообщениям, которые так и не удалось обработать.
+type DeadLetters interface {
+	DeadLetters(ctx context.Context, limit int) ([]Message, error)
+	// Replay возвращает сообщение в основную очередь со сброшенным счётчиком попыток.
+	Replay(ctx context.Context, id string) error
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку обработки как неисправимую: сообщение сразу
+// отправляется в очередь недоставленных без повторных попыток.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent сообщает, что ошибка помечена через Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
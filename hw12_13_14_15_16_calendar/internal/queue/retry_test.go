@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Backoff(i + 1); got != w {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, w, got)
+		}
+	}
+
+	if p.Exhausted(4) || !p.Exhausted(5) {
+		t.Fatalf("expected policy to be exhausted exactly after %d attempts", p.MaxAttempts)
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("bad payload")
+	err := fmt.Errorf("handle: %w", Permanent(base))
+
+	if !IsPermanent(err) {
+		t.Fatal("expected wrapped error to be permanent")
+	}
+	if !errors.Is(err, base) {
+		t.Fatal("expected permanent error to unwrap to the original one")
+	}
+	if IsPermanent(base) {
+		t.Fatal("plain error must not be permanent")
+	}
+}
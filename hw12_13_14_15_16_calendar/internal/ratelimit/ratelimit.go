@@ -0,0 +1,197 @@
+// Package ratelimit - ограничение частоты запросов к серверам алгоритмом token bucket.
+// У каждого клиента (аутентифицированного пользователя или IP адреса) своя корзина
+// на каждое правило: общее правило по умолчанию и отдельные правила для маршрутов.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
+)
+
+// Rule - Rate запросов в секунду в среднем и до Burst подряд. Rate <= 0 - без ограничения.
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+func (r Rule) unlimited() bool {
+	return r.Rate <= 0
+}
+
+func (r Rule) burst() float64 {
+	if r.Burst < 1 {
+		return 1
+	}
+	return float64(r.Burst)
+}
+
+// Config - правила ограничения. Ключ Routes - маршрут HTTP с методом или без него
+// ("POST /api/events", "/api/events") или полное имя метода gRPC ("/event.EventService/CreateEvent").
+// Маршруты без своего правила делят одну корзину клиента по правилу Default.
+// AuthFailures - отдельная корзина неудачных попыток аутентификации с одного IP;
+// без rate используется правило Default.
+type Config struct {
+	Enabled      bool
+	Default      Rule
+	Routes       map[string]Rule
+	AuthFailures Rule
+}
+
+// authFailuresKey - ключ корзины неудачных аутентификаций; не совпадает ни с одним маршрутом.
+const authFailuresKey = "\x00auth_failures"
+
+// idleTTL - через сколько без запросов корзина клиента удаляется.
+const idleTTL = 10 * time.Minute
+
+type Limiter struct {
+	cfg atomic.Pointer[Config]
+	now func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	rule   string
+	client string
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func New(cfg Config) *Limiter {
+	l := &Limiter{
+		now:     time.Now,
+		buckets: make(map[bucketKey]*bucket),
+	}
+	l.Configure(cfg)
+	return l
+}
+
+// Configure заменяет правила, например при перезагрузке конфигурации. Накопленные корзины
+// сбрасываются, чтобы новые значения burst применились сразу.
+func (l *Limiter) Configure(cfg Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg.Store(&cfg)
+	l.buckets = make(map[bucketKey]*bucket)
+}
+
+// Allow расходует токен клиента на запрос к маршруту. Если токена нет, возвращает false
+// и время, через которое запрос можно повторить. routes - ключи правил от более точного
+// к более общему; используется первое найденное, иначе правило по умолчанию.
+func (l *Limiter) Allow(client string, routes ...string) (bool, time.Duration) {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return true, 0
+	}
+
+	key, rule := "", cfg.Default
+	for _, r := range routes {
+		if rr, ok := cfg.Routes[r]; ok {
+			key, rule = r, rr
+			break
+		}
+	}
+	return l.take(client, key, rule, true)
+}
+
+// AuthAllowed сообщает, можно ли проверять учётные данные клиента: не исчерпал ли он
+// неудачные попытки. Токен не расходуется - его списывает AuthFailed.
+func (l *Limiter) AuthAllowed(client string) (bool, time.Duration) {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return true, 0
+	}
+	return l.take(client, authFailuresKey, cfg.authFailures(), false)
+}
+
+// AuthFailed списывает токен за неудачную попытку аутентификации клиента.
+func (l *Limiter) AuthFailed(client string) {
+	cfg := l.cfg.Load()
+	if !cfg.Enabled {
+		return
+	}
+	l.take(client, authFailuresKey, cfg.authFailures(), true)
+}
+
+func (c *Config) authFailures() Rule {
+	if c.AuthFailures.unlimited() {
+		return c.Default
+	}
+	return c.AuthFailures
+}
+
+// take пополняет корзину клиента по правилу и, если consume, расходует из неё токен.
+func (l *Limiter) take(client, key string, rule Rule, consume bool) (bool, time.Duration) {
+	if rule.unlimited() {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bk := bucketKey{rule: key, client: client}
+	b, ok := l.buckets[bk]
+	if !ok {
+		b = &bucket{tokens: rule.burst(), last: now}
+		l.buckets[bk] = b
+	}
+	b.tokens = math.Min(rule.burst(), b.tokens+now.Sub(b.last).Seconds()*rule.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		if consume {
+			b.tokens--
+		}
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep раз в минуту удаляет корзины клиентов, которые давно не обращались.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for k, b := range l.buckets {
+		if now.Sub(b.last) > idleTTL {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// RetryAfterSeconds округляет ожидание вверх до целых секунд для заголовка Retry-After.
+func RetryAfterSeconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
+
+// Client - ключ клиента для корзины: аутентифицированный пользователь, а без аутентификации - IP адрес.
+func Client(ctx context.Context, ip string) string {
+	if subject, ok := auth.SubjectFromContext(ctx); ok {
+		return "user:" + subject
+	}
+	return IPClient(ip)
+}
+
+// IPClient - ключ клиента по IP адресу, например для неудачных аутентификаций.
+func IPClient(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
+)
+
+func newTestLimiter(cfg Config) (*Limiter, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(cfg)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestBurstAndRefill(t *testing.T) {
+	l, now := newTestLimiter(Config{Enabled: true, Default: Rule{Rate: 2, Burst: 3}})
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("ip:1"); !ok {
+			t.Fatalf("request %d within burst rejected", i)
+		}
+	}
+	ok, wait := l.Allow("ip:1")
+	if ok {
+		t.Fatal("expected request over burst to be rejected")
+	}
+	if wait != 500*time.Millisecond {
+		t.Fatalf("expected wait 500ms, got %s", wait)
+	}
+	// корзины разных клиентов независимы
+	if ok, _ := l.Allow("ip:2"); !ok {
+		t.Fatal("expected other client to be allowed")
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("ip:1"); !ok {
+		t.Fatal("expected token to be refilled")
+	}
+	if ok, _ := l.Allow("ip:1"); ok {
+		t.Fatal("expected only one refilled token")
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		Enabled: true,
+		Default: Rule{Rate: 1, Burst: 1},
+		Routes: map[string]Rule{
+			"POST /api/events": {Rate: 1, Burst: 2},
+			"/api/events/day":  {},
+		},
+	})
+
+	// маршруты без правила делят общую корзину
+	if ok, _ := l.Allow("c", "GET /api/events", "/api/events"); !ok {
+		t.Fatal("expected first default request to be allowed")
+	}
+	if ok, _ := l.Allow("c", "GET /api/events/week", "/api/events/week"); ok {
+		t.Fatal("expected default bucket to be shared")
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("c", "POST /api/events", "/api/events"); !ok {
+			t.Fatalf("route request %d rejected", i)
+		}
+	}
+	if ok, _ := l.Allow("c", "POST /api/events", "/api/events"); ok {
+		t.Fatal("expected route bucket to be exhausted")
+	}
+
+	// правило без rate - без ограничения
+	for i := 0; i < 10; i++ {
+		if ok, _ := l.Allow("c", "GET /api/events/day", "/api/events/day"); !ok {
+			t.Fatal("expected unlimited route")
+		}
+	}
+}
+
+func TestDisabledAndConfigure(t *testing.T) {
+	l, _ := newTestLimiter(Config{Default: Rule{Rate: 1, Burst: 1}})
+	for i := 0; i < 5; i++ {
+		if ok, _ := l.Allow("c"); !ok {
+			t.Fatal("expected disabled limiter to allow everything")
+		}
+	}
+
+	l.Configure(Config{Enabled: true, Default: Rule{Rate: 1, Burst: 1}})
+	if ok, _ := l.Allow("c"); !ok {
+		t.Fatal("expected first request to be allowed")
+	}
+	if ok, _ := l.Allow("c"); ok {
+		t.Fatal("expected second request to be rejected")
+	}
+
+	// новые правила применяются сразу, накопленное состояние сбрасывается
+	l.Configure(Config{Enabled: true, Default: Rule{Rate: 1, Burst: 2}})
+	if ok, _ := l.Allow("c"); !ok {
+		t.Fatal("expected buckets to be reset on configure")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l, now := newTestLimiter(Config{Enabled: true, Default: Rule{Rate: 1, Burst: 1}})
+	l.Allow("a")
+	*now = now.Add(idleTTL + time.Minute)
+	l.Allow("b")
+	if _, ok := l.buckets[bucketKey{client: "a"}]; ok {
+		t.Fatal("expected idle bucket to be removed")
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	for d, want := range map[time.Duration]int{
+		0:                       1,
+		100 * time.Millisecond:  1,
+		time.Second:             1,
+		1500 * time.Millisecond: 2,
+	} {
+		if got := RetryAfterSeconds(d); got != want {
+			t.Errorf("RetryAfterSeconds(%s) = %d, want %d", d, got, want)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	if got := Client(context.Background(), "10.0.0.1"); got != "ip:10.0.0.1" {
+		t.Fatalf("expected ip client, got %q", got)
+	}
+	ctx := auth.ContextWithSubject(context.Background(), "alice")
+	if got := Client(ctx, "10.0.0.1"); got != "user:alice" {
+		t.Fatalf("expected user client, got %q", got)
+	}
+}
+
+func TestAuthFailures(t *testing.T) {
+	l, now := newTestLimiter(Config{
+		Enabled:      true,
+		Default:      Rule{Rate: 100, Burst: 100},
+		AuthFailures: Rule{Rate: 1, Burst: 2},
+	})
+
+	// проверка не расходует токены
+	for i := 0; i < 5; i++ {
+		if ok, _ := l.AuthAllowed("ip:1"); !ok {
+			t.Fatal("expected attempts to be allowed before failures")
+		}
+	}
+	l.AuthFailed("ip:1")
+	l.AuthFailed("ip:1")
+	ok, wait := l.AuthAllowed("ip:1")
+	if ok || wait != time.Second {
+		t.Fatalf("expected attempts to be blocked for 1s, got %v %s", ok, wait)
+	}
+	// неудачи не тратят корзину запросов и корзины других IP
+	if ok, _ := l.Allow("ip:1"); !ok {
+		t.Fatal("expected request bucket to be separate")
+	}
+	if ok, _ := l.AuthAllowed("ip:2"); !ok {
+		t.Fatal("expected other ip to be allowed")
+	}
+
+	*now = now.Add(time.Second)
+	if ok, _ := l.AuthAllowed("ip:1"); !ok {
+		t.Fatal("expected attempt to be allowed after refill")
+	}
+
+	// без своего правила - правило по умолчанию
+	l.Configure(Config{Enabled: true, Default: Rule{Rate: 1, Burst: 1}})
+	l.AuthFailed("ip:1")
+	if ok, _ := l.AuthAllowed("ip:1"); ok {
+		t.Fatal("expected default rule for auth failures")
+	}
+}
@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header - HTTP заголовок с идентификатором запроса.
+const Header = "X-Request-ID"
+
+// MetadataKey - ключ метаданных gRPC с идентификатором запроса.
+const MetadataKey = "x-request-id"
+
+const maxLen = 128
+
+// New генерирует новый идентификатор запроса.
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Valid проверяет идентификатор, пришедший от клиента: непустой, разумной длины
+// и только из печатных ASCII символов, чтобы его можно было безопасно писать в логи и заголовки.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// FromClient возвращает идентификатор клиента, если он корректен, иначе новый.
+func FromClient(id string) string {
+	if Valid(id) {
+		return id
+	}
+	return New()
+}
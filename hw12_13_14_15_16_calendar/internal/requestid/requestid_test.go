@@ -0,0 +1,18 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromClient(t *testing.T) {
+	if got := FromClient("abc-123"); got != "abc-123" {
+		t.Fatalf("expected client id to be kept, got %q", got)
+	}
+	for _, bad := range []string{"", "with space", "line\nbreak", strings.Repeat("a", maxLen+1)} {
+		got := FromClient(bad)
+		if got == bad || len(got) != 32 {
+			t.Fatalf("expected generated id for %q, got %q", bad, got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+	Debug(msg string)
+}
+
+// Storage - источник событий и учёт уже поставленных в очередь напоминаний.
+type Storage interface {
+	ListEvents(ctx context.Context) ([]storage.Event, error)
+	ClaimNotification(ctx context.Context, n storage.Notification, lease time.Duration) (bool, error)
+	ReleaseNotification(ctx context.Context, n storage.Notification) error
+	MarkNotificationPublished(ctx context.Context, n storage.Notification) error
+}
+
+// Publisher отправляет уведомление рассыльщику.
+type Publisher interface {
+	Publish(ctx context.Context, n storage.Notification) error
+}
+
+// defaultClaimLease - через сколько отметка "поставлено в очередь" без подтверждённой публикации
+// считается потерянной (планировщик упал между отметкой и публикацией) и напоминание ставится снова.
+const defaultClaimLease = 5 * time.Minute
+
+// Scheduler периодически сканирует события и ставит в очередь наступившие напоминания.
+// Перед публикацией напоминание отмечается в хранилище, а после подтверждения брокера
+// помечается опубликованным, поэтому перезапуск планировщика не приводит к повторной
+// публикации. Если публикация после отметки не подтвердилась (процесс упал), отметка
+// переходит к следующему тику по истечении lease. Опубликованные напоминания планировщик
+// больше не трогает - дальше за них отвечают очередь и рассыльщик, который берёт каждое
+// уведомление на доставку не больше одного раза (ClaimDelivery).
+type Scheduler struct {
+	logger    Logger
+	store     Storage
+	publisher Publisher
+	interval  time.Duration
+	lease     time.Duration
+}
+
+type Option func(*Scheduler)
+
+// WithClaimLease задаёт, через сколько неотправленное напоминание ставится в очередь повторно.
+func WithClaimLease(d time.Duration) Option {
+	return func(s *Scheduler) {
+		if d > 0 {
+			s.lease = d
+		}
+	}
+}
+
+func New(logger Logger, store Storage, publisher Publisher, interval time.Duration, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		logger:    logger,
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		lease:     defaultClaimLease,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx, time.Now()); err != nil {
+			s.logger.Error("scheduler tick failed: " + err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick ставит в очередь все напоминания, время которых наступило к моменту now.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	events, err := s.store.ListEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range dueNotifications(events, now) {
+		claimed, err := s.store.ClaimNotification(ctx, n, s.lease)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		if err := s.publisher.Publish(ctx, n); err != nil {
+			// снимаем отметку, чтобы попробовать ещё раз на следующем тике
+			if relErr := s.store.ReleaseNotification(ctx, n); relErr != nil {
+				s.logger.Error("failed to release notification: " + relErr.Error())
+			}
+			return fmt.Errorf("publish notification for event %s: %w", n.EventID, err)
+		}
+		if err := s.store.MarkNotificationPublished(ctx, n); err != nil {
+			// напоминание будет опубликовано ещё раз по истечении lease, дубликат отбросит рассыльщик
+			s.logger.Error("failed to mark notification published: " + err.Error())
+		}
+		metrics.ObserveQueueLag("scheduler", now.Sub(n.DueAt()))
+		s.logger.Debug(fmt.Sprintf("notification enqueued: event=%s channel=%s offset=%v",
+			n.EventID, n.Channel, n.Offset))
+	}
+	return nil
+}
+
+// dueNotifications выбирает напоминания, время которых наступило, а событие ещё не началось.
+func dueNotifications(events []storage.Event, now time.Time) []storage.Notification {
+	var out []storage.Notification
+	for _, e := range events {
+		if !e.At.After(now) {
+			continue
+		}
+		for _, r := range e.Reminders {
+			if e.At.Add(-r.Offset).After(now) {
+				continue
+			}
+			out = append(out, storage.Notification{
+				EventID: e.ID,
+				Title:   e.Title,
+				At:      e.At,
+				UserID:  e.UserID,
+				Offset:  r.Offset,
+				Channel: r.Channel,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+)
+
+type fakePublisher struct {
+	mu   sync.Mutex
+	sent []storage.Notification
+	err  error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, n storage.Notification) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.sent = append(p.sent, n)
+	return nil
+}
+
+func (p *fakePublisher) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sent)
+}
+
+func TestSchedulerExactlyOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	_ = store.CreateEvent(ctx, storage.Event{
+		ID:    "1",
+		Title: "meeting",
+		At:    now.Add(30 * time.Minute),
+		Reminders: []storage.Reminder{
+			{Offset: time.Hour, Channel: storage.ChannelLog},
+			{Offset: 10 * time.Minute, Channel: storage.ChannelEmail},
+		},
+	})
+
+	pub := &fakePublisher{}
+	s := New(logger.New("error"), store, pub, time.Minute)
+
+	if err := s.Tick(ctx, now); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", pub.count())
+	}
+
+	// повторный тик не должен отправлять то же напоминание
+	if err := s.Tick(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 1 {
+		t.Fatalf("expected 1 notification after second tick, got %d", pub.count())
+	}
+
+	// "перезапуск" планировщика с тем же хранилищем
+	restarted := New(logger.New("error"), store, pub, time.Minute)
+	if err := restarted.Tick(ctx, now.Add(25*time.Minute)); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 2 {
+		t.Fatalf("expected only the second reminder after restart, got %d notifications", pub.count())
+	}
+	if pub.sent[1].Channel != storage.ChannelEmail {
+		t.Fatalf("expected email reminder, got %s", pub.sent[1].Channel)
+	}
+}
+
+func TestSchedulerEventMoved(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	e := storage.Event{
+		ID:        "1",
+		Title:     "meeting",
+		At:        now.Add(10 * time.Minute),
+		Reminders: []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}},
+	}
+	_ = store.CreateEvent(ctx, e)
+
+	pub := &fakePublisher{}
+	s := New(logger.New("error"), store, pub, time.Minute)
+	_ = s.Tick(ctx, now)
+
+	// событие перенесли - напоминание для нового времени должно уйти ещё раз
+	e.At = now.Add(20 * time.Minute)
+	_ = store.UpdateEvent(ctx, e)
+	_ = s.Tick(ctx, now.Add(time.Minute))
+	_ = s.Tick(ctx, now.Add(2*time.Minute))
+
+	if pub.count() != 2 {
+		t.Fatalf("expected 2 notifications, got %d", pub.count())
+	}
+	if !pub.sent[1].At.Equal(e.At) {
+		t.Fatalf("expected notification for the new time %v, got %v", e.At, pub.sent[1].At)
+	}
+}
+
+func TestSchedulerPublishFailure(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	_ = store.CreateEvent(ctx, storage.Event{
+		ID:        "1",
+		Title:     "meeting",
+		At:        now.Add(10 * time.Minute),
+		Reminders: []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}},
+	})
+
+	pub := &fakePublisher{err: errors.New("queue is down")}
+	s := New(logger.New("error"), store, pub, time.Minute)
+
+	if err := s.Tick(ctx, now); err == nil {
+		t.Fatal("expected error when publish fails")
+	}
+
+	// после восстановления очереди напоминание должно уйти
+	pub.err = nil
+	if err := s.Tick(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 1 {
+		t.Fatalf("expected 1 notification, got %d", pub.count())
+	}
+}
+
+func TestSchedulerSkipsPastEvents(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	_ = store.CreateEvent(ctx, storage.Event{
+		ID:        "past",
+		At:        now.Add(-time.Minute),
+		Reminders: []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}},
+	})
+	_ = store.CreateEvent(ctx, storage.Event{
+		ID:        "future",
+		At:        now.Add(2 * time.Hour),
+		Reminders: []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}},
+	})
+
+	pub := &fakePublisher{}
+	s := New(logger.New("error"), store, pub, time.Minute)
+	_ = s.Tick(ctx, now)
+
+	if pub.count() != 0 {
+		t.Fatalf("expected no notifications, got %d", pub.count())
+	}
+}
+
+func TestSchedulerReclaimsLostClaim(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	now := time.Now()
+	_ = store.CreateEvent(ctx, storage.Event{
+		ID:        "1",
+		Title:     "meeting",
+		At:        now.Add(30 * time.Minute),
+		Reminders: []storage.Reminder{{Offset: time.Hour, Channel: storage.ChannelLog}},
+	})
+
+	// планировщик отметил напоминание и упал, не успев опубликовать
+	n := storage.Notification{EventID: "1", Title: "meeting", At: now.Add(30 * time.Minute), Offset: time.Hour, Channel: storage.ChannelLog}
+	if claimed, _ := store.ClaimNotification(ctx, n, time.Hour); !claimed {
+		t.Fatal("expected claim to succeed")
+	}
+
+	pub := &fakePublisher{}
+	s := New(logger.New("error"), store, pub, time.Minute, WithClaimLease(10*time.Millisecond))
+	if err := s.Tick(ctx, now); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 0 {
+		t.Fatal("expected fresh claim of another scheduler to be respected")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err := s.Tick(ctx, now); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 1 {
+		t.Fatalf("expected lost claim to be republished after lease, got %d", pub.count())
+	}
+
+	// опубликованное уведомление не переотправляется и после lease, даже если ещё
+	// не доставлено (ждёт в очереди повторов или недоставленных)
+	time.Sleep(20 * time.Millisecond)
+	if err := s.Tick(ctx, now); err != nil {
+		t.Fatalf("tick failed: %v", err)
+	}
+	if pub.count() != 1 {
+		t.Fatalf("expected published notification not to be republished, got %d", pub.count())
+	}
+}
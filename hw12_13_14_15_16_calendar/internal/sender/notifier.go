@@ -0,0 +1,44 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+// Notifier доставляет уведомление по конкретному каналу.
+type Notifier interface {
+	Notify(ctx context.Context, n storage.Notification) error
+}
+
+// LogNotifier "отправляет" уведомление в лог.
+type LogNotifier struct {
+	logger Logger
+}
+
+func NewLogNotifier(logger Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+func (l *LogNotifier) Notify(_ context.Context, n storage.Notification) error {
+	l.logger.Info(fmt.Sprintf("notification: event=%s title=%q at=%s user=%s",
+		n.EventID, n.Title, n.At.Format(time.RFC3339), n.UserID))
+	return nil
+}
+
+// EmailNotifier - заглушка отправки email: письмо только логируется.
+type EmailNotifier struct {
+	logger Logger
+}
+
+func NewEmailNotifier(logger Logger) *EmailNotifier {
+	return &EmailNotifier{logger: logger}
+}
+
+func (e *EmailNotifier) Notify(_ context.Context, n storage.Notification) error {
+	e.logger.Info(fmt.Sprintf("email to user %s: %q starts at %s",
+		n.UserID, n.Title, n.At.Format(time.RFC3339)))
+	return nil
+}
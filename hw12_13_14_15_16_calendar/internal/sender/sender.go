@@ -0,0 +1,113 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+	Debug(msg string)
+}
+
+// Storage - учёт доставки уведомлений.
+type Storage interface {
+	ClaimDelivery(ctx context.Context, n storage.Notification, lease time.Duration) (bool, error)
+	MarkNotificationSent(ctx context.Context, n storage.Notification) error
+	MarkNotificationFailed(ctx context.Context, n storage.Notification, dead bool) error
+}
+
+// defaultDeliveryLease - через сколько доставка, начатая упавшим рассыльщиком, перехватывается заново.
+const defaultDeliveryLease = 5 * time.Minute
+
+// Sender читает уведомления из очереди и доставляет их через Notifier нужного канала.
+// Перед доставкой уведомление берётся в хранилище (ClaimDelivery), поэтому дубликаты
+// сообщения и несколько рассыльщиков не приводят к повторной отправке.
+type Sender struct {
+	logger    Logger
+	consumer  queue.Consumer
+	store     Storage
+	notifiers map[string]Notifier
+	retry     queue.RetryPolicy
+	lease     time.Duration
+}
+
+type Option func(*Sender)
+
+// WithRetryPolicy задаёт политику повторов очереди: по ней рассыльщик определяет,
+// что неудачная доставка была последней и сообщение уходит в очередь недоставленных.
+func WithRetryPolicy(p queue.RetryPolicy) Option {
+	return func(s *Sender) {
+		s.retry = p.WithDefaults()
+	}
+}
+
+// WithDeliveryLease задаёт, через сколько незавершённая доставка перехватывается заново.
+func WithDeliveryLease(d time.Duration) Option {
+	return func(s *Sender) {
+		if d > 0 {
+			s.lease = d
+		}
+	}
+}
+
+func New(logger Logger, consumer queue.Consumer, store Storage, notifiers map[string]Notifier, opts ...Option) *Sender {
+	s := &Sender{
+		logger:    logger,
+		consumer:  consumer,
+		store:     store,
+		notifiers: notifiers,
+		retry:     queue.DefaultRetryPolicy(),
+		lease:     defaultDeliveryLease,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Sender) Run(ctx context.Context) error {
+	return s.consumer.Consume(ctx, s.Handle)
+}
+
+// Handle обрабатывает одно сообщение из очереди. Ошибка означает, что сообщение
+// нужно доставить повторно; сообщения, которые обработать невозможно, помечаются
+// queue.Permanent и сразу уходят в очередь недоставленных.
+func (s *Sender) Handle(ctx context.Context, msg queue.Message) error {
+	n, err := queue.DecodeNotification(msg)
+	if err != nil {
+		// повторная доставка не поможет
+		return queue.Permanent(fmt.Errorf("decode notification %s: %w", msg.ID, err))
+	}
+
+	notifier, ok := s.notifiers[n.Channel]
+	if !ok {
+		return queue.Permanent(fmt.Errorf("no notifier for channel %q", n.Channel))
+	}
+
+	claimed, err := s.store.ClaimDelivery(ctx, n, s.lease)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		s.logger.Debug("notification already sent, being sent or its event deleted, skipping: " + msg.ID)
+		return nil
+	}
+
+	if err := notifier.Notify(ctx, n); err != nil {
+		// та же проверка, по которой очередь решит, повторять ли сообщение
+		dead := s.retry.Exhausted(msg.Attempt + 1)
+		if markErr := s.store.MarkNotificationFailed(ctx, n, dead); markErr != nil {
+			s.logger.Error("failed to mark notification failed: " + markErr.Error())
+		}
+		return fmt.Errorf("notify via %s: %w", n.Channel, err)
+	}
+	metrics.ObserveQueueLag("sender", time.Since(n.DueAt()))
+	return s.store.MarkNotificationSent(ctx, n)
+}
@@ -0,0 +1,196 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	memoryqueue "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue/memory"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+)
+
+type fakeNotifier struct {
+	mu    sync.Mutex
+	got   []storage.Notification
+	fails int
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, n storage.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fails > 0 {
+		f.fails--
+		return errors.New("delivery failed")
+	}
+	f.got = append(f.got, n)
+	return nil
+}
+
+func (f *fakeNotifier) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.got)
+}
+
+func TestSenderDeliversOnce(t *testing.T) {
+	ctx := context.Background()
+
+	store := memorystorage.New()
+	at := time.Now().Add(time.Hour)
+	_ = store.CreateEvent(ctx, storage.Event{ID: "1", Title: "meeting", At: at})
+
+	n := storage.Notification{EventID: "1", Title: "meeting", At: at, Offset: time.Hour, Channel: storage.ChannelLog}
+	msg, _ := queue.EncodeNotification(n)
+
+	notifier := &fakeNotifier{fails: 1}
+	s := New(logger.New("error"), memoryqueue.New(queue.RetryPolicy{}), store, map[string]Notifier{storage.ChannelLog: notifier})
+
+	// неудачная доставка возвращает ошибку, чтобы сообщение вернулось в очередь
+	if err := s.Handle(ctx, msg); err == nil {
+		t.Fatal("expected error on failed delivery")
+	}
+	if err := s.Handle(ctx, msg); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	// дубликат сообщения не должен привести к повторной отправке
+	if err := s.Handle(ctx, msg); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+
+	if notifier.count() != 1 {
+		t.Fatalf("expected exactly one delivery, got %d", notifier.count())
+	}
+	if sent, _ := store.IsNotificationSent(ctx, n); !sent {
+		t.Fatal("expected notification to be marked as sent")
+	}
+}
+
+// blockingNotifier задерживает доставку, пока не закрыт release.
+type blockingNotifier struct {
+	fakeNotifier
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingNotifier) Notify(ctx context.Context, n storage.Notification) error {
+	b.started <- struct{}{}
+	<-b.release
+	return b.fakeNotifier.Notify(ctx, n)
+}
+
+func TestSenderConcurrentDuplicates(t *testing.T) {
+	ctx := context.Background()
+	store := memorystorage.New()
+	at := time.Now().Add(time.Hour)
+	n := storage.Notification{EventID: "1", Title: "meeting", At: at, Offset: time.Hour, Channel: storage.ChannelLog}
+	msg, _ := queue.EncodeNotification(n)
+
+	notifier := &blockingNotifier{started: make(chan struct{}, 2), release: make(chan struct{})}
+	s := New(logger.New("error"), memoryqueue.New(queue.RetryPolicy{}), store, map[string]Notifier{storage.ChannelLog: notifier})
+
+	// первый рассыльщик взял уведомление и доставляет его
+	done := make(chan error, 1)
+	go func() { done <- s.Handle(ctx, msg) }()
+	<-notifier.started
+
+	// дубликат, пришедший в это время другому рассыльщику, не доставляется
+	if err := s.Handle(ctx, msg); err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	close(notifier.release)
+	if err := <-done; err != nil {
+		t.Fatalf("handle failed: %v", err)
+	}
+	if notifier.count() != 1 {
+		t.Fatalf("expected exactly one delivery, got %d", notifier.count())
+	}
+}
+
+// deadRecorder запоминает, какие неудачные доставки были отмечены последними.
+type deadRecorder struct {
+	*memorystorage.Storage
+	dead []bool
+}
+
+func (r *deadRecorder) MarkNotificationFailed(ctx context.Context, n storage.Notification, dead bool) error {
+	r.dead = append(r.dead, dead)
+	return r.Storage.MarkNotificationFailed(ctx, n, dead)
+}
+
+func TestSenderMarksDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	store := &deadRecorder{Storage: memorystorage.New()}
+	at := time.Now().Add(time.Hour)
+	n := storage.Notification{EventID: "1", Title: "meeting", At: at, Offset: time.Hour, Channel: storage.ChannelLog}
+	msg, _ := queue.EncodeNotification(n)
+
+	notifier := &fakeNotifier{fails: 2}
+	s := New(logger.New("error"), memoryqueue.New(queue.RetryPolicy{}), store,
+		map[string]Notifier{storage.ChannelLog: notifier}, WithRetryPolicy(queue.RetryPolicy{MaxAttempts: 2}))
+
+	if err := s.Handle(ctx, msg); err == nil {
+		t.Fatal("expected error on failed delivery")
+	}
+	msg.Attempt = 1
+	if err := s.Handle(ctx, msg); err == nil {
+		t.Fatal("expected error on failed delivery")
+	}
+	if len(store.dead) != 2 || store.dead[0] || !store.dead[1] {
+		t.Fatalf("expected only the last failure to be dead-lettered, got %v", store.dead)
+	}
+
+	// сообщение, возвращённое из очереди недоставленных, доставляется
+	msg.Attempt = 0
+	if err := s.Handle(ctx, msg); err != nil {
+		t.Fatalf("handle of replayed message failed: %v", err)
+	}
+	if sent, _ := store.IsNotificationSent(ctx, n); !sent {
+		t.Fatal("expected replayed notification to be sent")
+	}
+}
+
+func TestSenderRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := memorystorage.New()
+	at := time.Now().Add(time.Hour)
+	_ = store.CreateEvent(ctx, storage.Event{ID: "1", Title: "meeting", At: at})
+
+	q := memoryqueue.New(queue.RetryPolicy{})
+	msg, _ := queue.EncodeNotification(storage.Notification{
+		EventID: "1", Title: "meeting", At: at, Offset: time.Hour, Channel: storage.ChannelLog,
+	})
+	_ = q.Publish(ctx, msg)
+
+	notifier := &fakeNotifier{}
+	s := New(logger.New("error"), q, store, map[string]Notifier{storage.ChannelLog: notifier})
+	go func() { _ = s.Run(ctx) }()
+
+	for notifier.count() == 0 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if notifier.count() != 1 {
+		t.Fatalf("expected one delivery, got %d", notifier.count())
+	}
+}
+
+func TestSenderPoisonMessages(t *testing.T) {
+	s := New(logger.New("error"), memoryqueue.New(queue.RetryPolicy{}), memorystorage.New(), nil)
+
+	err := s.Handle(context.Background(), queue.Message{ID: "bad", Body: []byte("{")})
+	if !queue.IsPermanent(err) {
+		t.Fatalf("expected undecodable message to fail permanently, got %v", err)
+	}
+
+	msg, _ := queue.EncodeNotification(storage.Notification{EventID: "1", Channel: "pigeon"})
+	err = s.Handle(context.Background(), msg)
+	if !queue.IsPermanent(err) {
+		t.Fatalf("expected unknown channel to fail permanently, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+// Заголовки запроса webhook.
+const (
+	HeaderWebhookSignature = "X-Calendar-Signature"
+	HeaderWebhookTimestamp = "X-Calendar-Timestamp"
+	HeaderWebhookDelivery  = "X-Calendar-Delivery"
+	HeaderWebhookAttempt   = "X-Calendar-Attempt"
+)
+
+// WebhookEndpoint - адрес получателя и секрет для подписи запросов к нему.
+type WebhookEndpoint struct {
+	URL    string
+	Secret string // если пусто, используется WebhookConfig.Secret
+}
+
+type WebhookConfig struct {
+	Endpoints   map[string]WebhookEndpoint // по UserID
+	Secret      string
+	Timeout     time.Duration
+	MaxAttempts int
+	Backoff     time.Duration // пауза перед второй попыткой, далее удваивается
+}
+
+// DeliveryRecorder сохраняет результаты попыток доставки на webhook.
+type DeliveryRecorder interface {
+	RecordWebhookDelivery(ctx context.Context, d storage.WebhookDelivery) error
+}
+
+// WebhookNotifier отправляет уведомление POST запросом с JSON телом на адрес,
+// настроенный для пользователя. Строка "<timestamp>.<тело>" подписывается HMAC-SHA256:
+// время отправки (unix, секунды) передаётся в X-Calendar-Timestamp, подпись -
+// в X-Calendar-Signature в виде "sha256=<hex>". По времени получатель отбрасывает
+// повторно присланные (перехваченные) запросы.
+type WebhookNotifier struct {
+	logger   Logger
+	settings atomic.Pointer[webhookSettings]
+	recorder DeliveryRecorder
+}
+
+// webhookSettings заменяются целиком, поэтому доставка, начатая до Configure,
+// доходит до конца со старыми настройками.
+type webhookSettings struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier создаёт notifier; recorder может быть nil.
+func NewWebhookNotifier(logger Logger, cfg WebhookConfig, recorder DeliveryRecorder) *WebhookNotifier {
+	w := &WebhookNotifier{
+		logger:   logger,
+		recorder: recorder,
+	}
+	w.Configure(cfg)
+	return w
+}
+
+// Configure заменяет настройки доставки (адреса, секреты, таймаут, попытки) без перезапуска.
+func (w *WebhookNotifier) Configure(cfg WebhookConfig) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 500 * time.Millisecond
+	}
+	w.settings.Store(&webhookSettings{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// перенаправление - ошибка настройки адреса: POST превратился бы в GET без тела
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	})
+}
+
+// SignWebhook возвращает значение заголовка подписи для времени отправки
+// (значение X-Calendar-Timestamp) и тела запроса.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify доставляет уведомление; доставленным считается только ответ 2xx. Ответы 5xx и сетевые
+// ошибки повторяются до MaxAttempts раз, после чего возвращается ошибка и сообщение вернётся
+// в очередь. Ответы 3xx и 4xx, отсутствие адреса или секрета у пользователя повторной доставкой
+// не исправить - такие ошибки помечаются queue.Permanent.
+func (w *WebhookNotifier) Notify(ctx context.Context, n storage.Notification) error {
+	settings := w.settings.Load()
+	cfg := settings.cfg
+
+	endpoint, ok := cfg.Endpoints[n.UserID]
+	if !ok || endpoint.URL == "" {
+		return queue.Permanent(fmt.Errorf("no webhook configured for user %q", n.UserID))
+	}
+	secret := endpoint.Secret
+	if secret == "" {
+		secret = cfg.Secret
+	}
+	if secret == "" {
+		// без секрета подпись ничего не доказывает
+		return queue.Permanent(fmt.Errorf("no webhook secret configured for user %q", n.UserID))
+	}
+
+	msg, err := queue.EncodeNotification(n)
+	if err != nil {
+		return queue.Permanent(err)
+	}
+
+	backoff := cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		status, err := post(ctx, settings.client, endpoint.URL, msg, secret, attempt)
+		w.record(ctx, n, endpoint.URL, attempt, status, err)
+
+		switch {
+		case queue.IsPermanent(err):
+			return err
+		case err == nil && status >= http.StatusOK && status < http.StatusMultipleChoices:
+			return nil
+		case err == nil && status < http.StatusInternalServerError:
+			return queue.Permanent(fmt.Errorf("webhook %s responded with status %d", endpoint.URL, status))
+		case err == nil:
+			err = fmt.Errorf("webhook %s responded with status %d", endpoint.URL, status)
+		}
+
+		if attempt >= cfg.MaxAttempts {
+			return err
+		}
+		w.logger.Debug(fmt.Sprintf("webhook attempt %d failed, retrying in %s: %v", attempt, backoff, err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post отправляет одну попытку; время подписи у каждой попытки своё.
+func post(ctx context.Context, client *http.Client, url string, msg queue.Message, secret string,
+	attempt int,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(msg.Body))
+	if err != nil {
+		return 0, queue.Permanent(err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", msg.ContentType)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(secret, timestamp, msg.Body))
+	req.Header.Set(HeaderWebhookDelivery, msg.ID)
+	req.Header.Set(HeaderWebhookAttempt, strconv.Itoa(attempt))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// дочитываем тело, чтобы соединение можно было переиспользовать
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+func (w *WebhookNotifier) record(ctx context.Context, n storage.Notification, url string, attempt, status int,
+	reqErr error,
+) {
+	if w.recorder == nil {
+		return
+	}
+	d := storage.WebhookDelivery{
+		Notification: n,
+		URL:          url,
+		Attempt:      attempt,
+		StatusCode:   status,
+		DeliveredAt:  time.Now(),
+	}
+	if reqErr != nil {
+		d.Error = reqErr.Error()
+	}
+	if err := w.recorder.RecordWebhookDelivery(ctx, d); err != nil {
+		w.logger.Error("failed to record webhook delivery: " + err.Error())
+	}
+}
@@ -0,0 +1,215 @@
+package sender
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+)
+
+func newWebhookFixture(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *memorystorage.Storage, storage.Notification) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	store := memorystorage.New()
+	at := time.Now().Add(time.Hour).UTC()
+	_ = store.CreateEvent(context.Background(), storage.Event{ID: "1", Title: "meeting", At: at, UserID: "user1"})
+
+	n := storage.Notification{
+		EventID: "1", Title: "meeting", At: at, UserID: "user1",
+		Offset: time.Hour, Channel: storage.ChannelWebhook,
+	}
+	return srv, store, n
+}
+
+func webhookConfig(url string) WebhookConfig {
+	return WebhookConfig{
+		Endpoints:   map[string]WebhookEndpoint{"user1": {URL: url}},
+		Secret:      "s3cret",
+		MaxAttempts: 3,
+		Backoff:     time.Millisecond,
+	}
+}
+
+func TestWebhookNotifierSignsBody(t *testing.T) {
+	var gotBody []byte
+	var gotSignature, gotTimestamp, gotDelivery string
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(HeaderWebhookSignature)
+		gotTimestamp = r.Header.Get(HeaderWebhookTimestamp)
+		gotDelivery = r.Header.Get(HeaderWebhookDelivery)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), webhookConfig(srv.URL), store)
+	if err := notifier.Notify(context.Background(), n); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	if !hmac.Equal([]byte(gotSignature), []byte(SignWebhook("s3cret", gotTimestamp, gotBody))) {
+		t.Fatalf("invalid signature %q", gotSignature)
+	}
+	ts, err := strconv.ParseInt(gotTimestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)).Abs() > time.Minute {
+		t.Fatalf("expected current unix timestamp, got %q", gotTimestamp)
+	}
+	// подпись привязана ко времени: запрос нельзя переслать позже с новым временем
+	if gotSignature == SignWebhook("s3cret", strconv.FormatInt(ts+600, 10), gotBody) {
+		t.Fatal("expected signature to depend on timestamp")
+	}
+	if gotDelivery != n.Key() {
+		t.Fatalf("expected delivery id %q, got %q", n.Key(), gotDelivery)
+	}
+	decoded, err := queue.DecodeNotification(queue.Message{Body: gotBody})
+	if err != nil {
+		t.Fatalf("failed to decode webhook body: %v", err)
+	}
+	if decoded.EventID != n.EventID || !decoded.At.Equal(n.At) {
+		t.Fatalf("unexpected webhook body: %+v", decoded)
+	}
+
+	deliveries, _ := store.WebhookDeliveries(context.Background(), "1")
+	if len(deliveries) != 1 || deliveries[0].StatusCode != http.StatusNoContent {
+		t.Fatalf("expected one recorded delivery with status 204, got %+v", deliveries)
+	}
+}
+
+func TestWebhookNotifierRetriesServerErrors(t *testing.T) {
+	var calls int32
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), webhookConfig(srv.URL), store)
+	if err := notifier.Notify(context.Background(), n); err != nil {
+		t.Fatalf("notify failed: %v", err)
+	}
+
+	deliveries, _ := store.WebhookDeliveries(context.Background(), "1")
+	if len(deliveries) != 3 {
+		t.Fatalf("expected 3 recorded attempts, got %d", len(deliveries))
+	}
+	if deliveries[0].StatusCode != http.StatusBadGateway || deliveries[2].StatusCode != http.StatusOK {
+		t.Fatalf("unexpected recorded statuses: %+v", deliveries)
+	}
+}
+
+func TestWebhookNotifierGivesUp(t *testing.T) {
+	var calls int32
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), webhookConfig(srv.URL), store)
+	err := notifier.Notify(context.Background(), n)
+	if err == nil || queue.IsPermanent(err) {
+		t.Fatalf("expected retryable error, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestWebhookNotifierClientErrors(t *testing.T) {
+	var calls int32
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGone)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), webhookConfig(srv.URL), store)
+	if err := notifier.Notify(context.Background(), n); !queue.IsPermanent(err) {
+		t.Fatalf("expected permanent error on 4xx, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("4xx must not be retried, got %d attempts", calls)
+	}
+
+	n.UserID = "unknown"
+	if err := notifier.Notify(context.Background(), n); !queue.IsPermanent(err) {
+		t.Fatalf("expected permanent error for user without webhook, got %v", err)
+	}
+}
+
+func TestWebhookNotifierRedirectIsNotDelivery(t *testing.T) {
+	var calls int32
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Redirect(w, r, "/moved", http.StatusFound)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), webhookConfig(srv.URL), store)
+	if err := notifier.Notify(context.Background(), n); !queue.IsPermanent(err) {
+		t.Fatalf("expected permanent error on 3xx, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("redirect must not be followed, got %d requests", calls)
+	}
+}
+
+func TestWebhookNotifierRequiresSecret(t *testing.T) {
+	var calls int32
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	cfg := webhookConfig(srv.URL)
+	cfg.Secret = ""
+	notifier := NewWebhookNotifier(logger.New("error"), cfg, store)
+	if err := notifier.Notify(context.Background(), n); !queue.IsPermanent(err) {
+		t.Fatalf("expected permanent error without secret, got %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 0 {
+		t.Fatal("expected unsigned webhook not to be sent")
+	}
+
+	cfg.Endpoints["user1"] = WebhookEndpoint{URL: srv.URL, Secret: "per-user"}
+	notifier.Configure(cfg)
+	if err := notifier.Notify(context.Background(), n); err != nil {
+		t.Fatalf("expected endpoint secret to be used, got %v", err)
+	}
+}
+
+func TestWebhookNotifierConfigure(t *testing.T) {
+	var gotSignature, gotTimestamp string
+	var gotBody []byte
+	srv, store, n := newWebhookFixture(t, func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get(HeaderWebhookSignature)
+		gotTimestamp = r.Header.Get(HeaderWebhookTimestamp)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	notifier := NewWebhookNotifier(logger.New("error"), WebhookConfig{}, store)
+	if err := notifier.Notify(context.Background(), n); !queue.IsPermanent(err) {
+		t.Fatalf("expected permanent error before endpoint is configured, got %v", err)
+	}
+
+	cfg := webhookConfig(srv.URL)
+	cfg.Secret = "rotated"
+	notifier.Configure(cfg)
+	if err := notifier.Notify(context.Background(), n); err != nil {
+		t.Fatalf("notify failed after reconfigure: %v", err)
+	}
+	if gotSignature != SignWebhook("rotated", gotTimestamp, gotBody) {
+		t.Fatalf("expected body signed with the new secret, got %q", gotSignature)
+	}
+}
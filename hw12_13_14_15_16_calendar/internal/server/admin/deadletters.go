@@ -0,0 +1,90 @@
+package adminserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+)
+
+const defaultDeadLettersLimit = 100
+
+type deadLetterResponse struct {
+	ID          string          `json:"id"`
+	Attempt     int             `json:"attempt"`
+	LastError   string          `json:"last_error"`
+	ContentType string          `json:"content_type"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	RawBody     string          `json:"raw_body,omitempty"` // если тело не JSON
+}
+
+// RegisterDeadLetters добавляет эндпоинты просмотра и повторной отправки недоставленных сообщений:
+//
+//	GET  /admin/dead-letters?limit=N
+//	POST /admin/dead-letters/replay?id=ID
+func (s *Server) RegisterDeadLetters(dl queue.DeadLetters) {
+	s.mux.HandleFunc("/admin/dead-letters", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit := defaultDeadLettersLimit
+		if v := r.URL.Query().Get("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				respondError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = n
+		}
+
+		msgs, err := dl.DeadLetters(r.Context(), limit)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		response := make([]deadLetterResponse, 0, len(msgs))
+		for _, m := range msgs {
+			item := deadLetterResponse{
+				ID:          m.ID,
+				Attempt:     m.Attempt,
+				LastError:   m.LastError,
+				ContentType: m.ContentType,
+			}
+			if json.Valid(m.Body) {
+				item.Body = m.Body
+			} else {
+				item.RawBody = string(m.Body)
+			}
+			response = append(response, item)
+		}
+		respondJSON(w, http.StatusOK, response)
+	})
+
+	s.mux.HandleFunc("/admin/dead-letters/replay", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			respondError(w, http.StatusBadRequest, "id parameter is required")
+			return
+		}
+
+		if err := dl.Replay(r.Context(), id); err != nil {
+			if errors.Is(err, queue.ErrMessageNotFound) {
+				respondError(w, http.StatusNotFound, "Message not found")
+				return
+			}
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]bool{"success": true})
+	})
+}
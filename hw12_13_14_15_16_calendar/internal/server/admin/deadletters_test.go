@@ -0,0 +1,64 @@
+package adminserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue"
+	memoryqueue "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/queue/memory"
+)
+
+func TestDeadLettersListAndReplay(t *testing.T) {
+	q := memoryqueue.New(queue.RetryPolicy{MaxAttempts: 1, InitialBackoff: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = q.Publish(ctx, queue.Message{ID: "n-1", ContentType: "application/json", Body: []byte(`{"event_id":"1"}`)})
+	_ = q.Consume(ctx, func(ctx context.Context, _ queue.Message) error {
+		defer cancel()
+		return errors.New("smtp is down")
+	})
+
+	srv := NewServer(logger.New("error"), "127.0.0.1", 0)
+	srv.RegisterDeadLetters(q)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dead-letters", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp []deadLetterResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp) != 1 || resp[0].ID != "n-1" || resp[0].LastError != "smtp is down" {
+		t.Fatalf("unexpected dead letters: %+v", resp)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/dead-letters/replay?id=n-1", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if q.Len() != 1 {
+		t.Fatalf("expected replayed message in queue, got %d", q.Len())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/dead-letters/replay?id=n-1", nil)
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for replayed message, got %d", w.Code)
+	}
+}
@@ -0,0 +1,66 @@
+package adminserver
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LevelController - логгер, уровень которого можно менять на лету.
+type LevelController interface {
+	Levels() (string, map[string]string)
+	SetLevel(level string) error
+	SetComponentLevel(component, level string) error
+	SetLevels(level string, components map[string]string) error
+}
+
+type logLevelRequest struct {
+	Level      string            `json:"level"`
+	Component  string            `json:"component,omitempty"`
+	Components map[string]string `json:"components,omitempty"`
+}
+
+type logLevelResponse struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// RegisterLogLevel добавляет эндпоинт управления уровнем логирования:
+//
+//	GET /admin/log-level
+//	PUT /admin/log-level {"level": "debug"}                              - общий уровень
+//	PUT /admin/log-level {"component": "sender", "level": "debug"}       - уровень компонента, "" - сброс
+//	PUT /admin/log-level {"level": "info", "components": {"http": "debug"}} - всё разом
+func (s *Server) RegisterLogLevel(lc LevelController) {
+	s.mux.HandleFunc("/admin/log-level", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req logLevelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				respondError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+				return
+			}
+
+			var err error
+			switch {
+			case req.Component != "":
+				err = lc.SetComponentLevel(req.Component, req.Level)
+			case req.Components != nil:
+				err = lc.SetLevels(req.Level, req.Components)
+			default:
+				err = lc.SetLevel(req.Level)
+			}
+			if err != nil {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			s.logger.Info("log level changed via admin endpoint")
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		level, components := lc.Levels()
+		respondJSON(w, http.StatusOK, logLevelResponse{Level: level, Components: components})
+	})
+}
@@ -0,0 +1,46 @@
+package adminserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+)
+
+func TestLogLevelEndpoint(t *testing.T) {
+	logg := logger.New("info", logger.WithOutput(&bytes.Buffer{}))
+	srv := NewServer(logg, "127.0.0.1", 0)
+	srv.RegisterLogLevel(logg)
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/admin/log-level", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := put(`{"level": "debug"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put(`{"component": "sender", "level": "error"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := put(`{"level": "verbose"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown level, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/log-level", nil)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	var resp logLevelResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Level != "debug" || resp.Components["sender"] != "error" {
+		t.Fatalf("unexpected levels: %+v", resp)
+	}
+}
@@ -0,0 +1,87 @@
+package adminserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server - служебный HTTP сервер фоновых процессов (планировщика, рассыльщика).
+type Server struct {
+	logger  Logger
+	host    string
+	port    int
+	mux     *http.ServeMux
+	httpSrv *http.Server
+}
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+	Debug(msg string)
+}
+
+func NewServer(logger Logger, host string, port int) *Server {
+	s := &Server{
+		logger: logger,
+		host:   host,
+		port:   port,
+		mux:    http.NewServeMux(),
+	}
+
+	s.httpSrv = &http.Server{
+		Handler:      s.mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	return s
+}
+
+// Handle регистрирует обработчик служебного эндпоинта.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	addr := fmt.Sprintf("%s:%d", s.host, s.port)
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("admin server listening on " + addr)
+
+	go func() {
+		if err := s.httpSrv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			s.logger.Error("admin serve error: " + err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	return s.Stop(context.Background())
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("shutting down admin server")
+	ctxShut, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return s.httpSrv.Shutdown(ctxShut)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, errorResponse{Error: message})
+}
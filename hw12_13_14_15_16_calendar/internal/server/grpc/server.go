@@ -2,15 +2,31 @@ package grpcserver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/api/event"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/ratelimit"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -24,12 +40,52 @@ type Server struct {
 	host    string
 	port    int
 	grpcSrv *grpc.Server
+	auth    Authenticator
+	limiter RateLimiter
+	tls     *tls.Config
+}
+
+// Authenticator проверяет учётные данные запроса и возвращает субъект.
+type Authenticator interface {
+	Authenticate(credential string) (string, error)
+}
+
+// RateLimiter расходует токен клиента на вызов метода и ограничивает неудачные
+// попытки аутентификации с одного IP (см. ratelimit.Limiter).
+type RateLimiter interface {
+	Allow(client string, routes ...string) (bool, time.Duration)
+	AuthAllowed(client string) (bool, time.Duration)
+	AuthFailed(client string)
+}
+
+type Option func(*Server)
+
+// WithAuth требует аутентификации для методов EventService; health и reflection доступны без неё.
+func WithAuth(a Authenticator) Option {
+	return func(s *Server) {
+		s.auth = a
+	}
+}
+
+// WithRateLimit ограничивает частоту вызовов методов EventService.
+func WithRateLimit(l RateLimiter) Option {
+	return func(s *Server) {
+		s.limiter = l
+	}
+}
+
+// WithTLS включает TLS; для mTLS в cfg должна быть задана проверка клиентских сертификатов.
+func WithTLS(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.tls = cfg
+	}
 }
 
 type Logger interface {
 	Info(msg string)
 	Error(msg string)
 	Debug(msg string)
+	InfoContext(ctx context.Context, msg string, args ...any)
 }
 
 type Application interface {
@@ -43,17 +99,31 @@ type Application interface {
 	ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error)
 }
 
-func NewServer(logger Logger, app Application, host string, port int) *Server {
+func NewServer(logger Logger, app Application, host string, port int, opts ...Option) *Server {
 	s := &Server{
 		logger: logger,
 		app:    app,
 		host:   host,
 		port:   port,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
 
-	grpcSrv := grpc.NewServer(
-		grpc.UnaryInterceptor(loggingInterceptor(logger)),
-	)
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			requestIDInterceptor(),
+			tracingInterceptor(),
+			loggingInterceptor(logger),
+			metricsInterceptor(),
+			authInterceptor(s.auth, s.limiter, logger),
+			rateLimitInterceptor(s.limiter),
+		),
+	}
+	if s.tls != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+	grpcSrv := grpc.NewServer(serverOpts...)
 	event.RegisterEventServiceServer(grpcSrv, s)
 	// Включаем reflection для grpcurl
 	reflection.Register(grpcSrv)
@@ -62,6 +132,11 @@ func NewServer(logger Logger, app Application, host string, port int) *Server {
 	return s
 }
 
+// RegisterHealth подключает сервис grpc.health.v1.Health. Вызывать до Start.
+func (s *Server) RegisterHealth(srv healthpb.HealthServer) {
+	healthpb.RegisterHealthServer(s.grpcSrv, srv)
+}
+
 func (s *Server) Start(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%d", s.host, s.port)
 	ln, err := net.Listen("tcp", addr)
@@ -98,7 +173,7 @@ func (s *Server) Stop(ctx context.Context) error {
 }
 
 // loggingInterceptor логирует каждый GRPC запрос
-func loggingInterceptor(logger Logger) grpc.UnaryServerInterceptor {
+func loggingInterceptor(logg Logger) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		start := time.Now()
 		resp, err := handler(ctx, req)
@@ -113,7 +188,134 @@ func loggingInterceptor(logger Logger) grpc.UnaryServerInterceptor {
 			}
 		}
 
-		logger.Info(fmt.Sprintf("GRPC %s - %s - %v - %v", info.FullMethod, statusCode, latency, err))
+		// request_id и trace_id добавляются полями из контекста запроса
+		logg.InfoContext(ctx, fmt.Sprintf("GRPC %s - %s - %v - %v", info.FullMethod, statusCode, latency, err))
+		return resp, err
+	}
+}
+
+// requestIDInterceptor берёт x-request-id из метаданных клиента или генерирует новый,
+// возвращает его в заголовках ответа и кладёт в контекст для логов; там же начинается
+// сессия хранилища для read-your-writes
+func requestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(requestid.MetadataKey); len(v) > 0 {
+				id = v[0]
+			}
+		}
+		id = requestid.FromClient(id)
+		// ошибка возможна только вне настоящего gRPC вызова, например в тестах
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+		return handler(storage.WithSession(logger.ContextWithRequestID(ctx, id)), req)
+	}
+}
+
+// authInterceptor проверяет токен (authorization: Bearer) или API ключ (x-api-key) из метаданных
+// и кладёт субъект в контекст. Стоит последним, чтобы отказы попадали в логи и метрики.
+// Подбор учётных данных ограничивается по IP (l) до их проверки.
+func authInterceptor(a Authenticator, l RateLimiter, logg Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if a == nil || !isEventService(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ipClient := ratelimit.IPClient(peerIP(ctx))
+		if l != nil {
+			if ok, wait := l.AuthAllowed(ipClient); !ok {
+				return nil, resourceExhausted(ctx, wait, "too many failed authentication attempts")
+			}
+		}
+		md, _ := metadata.FromIncomingContext(ctx)
+		subject, err := a.Authenticate(auth.Credential(first(md, "authorization"), first(md, "x-api-key")))
+		if err != nil {
+			if l != nil {
+				l.AuthFailed(ipClient)
+			}
+			logg.InfoContext(ctx, "authentication failed", "method", info.FullMethod, "err", err)
+			return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		return handler(auth.ContextWithSubject(ctx, subject), req)
+	}
+}
+
+// rateLimitInterceptor отклоняет вызов с ResourceExhausted, если клиент исчерпал запросы
+// к методу; время до повтора передаётся в заголовке retry-after (секунды).
+func rateLimitInterceptor(l RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if l == nil || !isEventService(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		if ok, wait := l.Allow(ratelimit.Client(ctx, peerIP(ctx)), info.FullMethod); !ok {
+			return nil, resourceExhausted(ctx, wait, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// resourceExhausted передаёт время до повтора в заголовке retry-after (секунды).
+func resourceExhausted(ctx context.Context, wait time.Duration, msg string) error {
+	retry := strconv.Itoa(ratelimit.RetryAfterSeconds(wait))
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", retry))
+	return status.Errorf(codes.ResourceExhausted, "%s, retry after %ss", msg, retry)
+}
+
+// peerIP - адрес клиента из соединения. Метаданные x-forwarded-for не учитываются:
+// их может подставить сам клиент.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
+
+func isEventService(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+event.EventService_ServiceDesc.ServiceName+"/")
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// tracingInterceptor открывает серверный спан, продолжая трассу из метаданных запроса
+func tracingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = tracing.ExtractIncoming(ctx)
+		ctx, span := tracing.Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.method", info.FullMethod),
+				attribute.String("request.id", logger.RequestIDFromContext(ctx)),
+			))
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if err != nil {
+			span.RecordError(err)
+			if code != codes.NotFound && code != codes.InvalidArgument && code != codes.AlreadyExists {
+				span.SetStatus(otelcodes.Error, err.Error())
+			}
+		}
+		return resp, err
+	}
+}
+
+// metricsInterceptor считает GRPC запросы и их длительность по методу
+func metricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
 		return resp, err
 	}
 }
@@ -136,8 +338,23 @@ func protoEventToDomain(pb *event.Event) (storage.Event, error) {
 		e.Duration = pb.GetDuration().AsDuration()
 	}
 
-	if pb.GetNotifyBefore() != nil {
-		e.NotifyBefore = pb.GetNotifyBefore().AsDuration()
+	for _, r := range pb.GetReminders() {
+		channel, ok := storage.ParseChannel(r.GetChannel())
+		if !ok {
+			return storage.Event{}, fmt.Errorf("unknown reminder channel %q", r.GetChannel())
+		}
+		e.Reminders = append(e.Reminders, storage.Reminder{
+			Offset:  r.GetOffset().AsDuration(),
+			Channel: channel,
+		})
+	}
+
+	// notify_before учитываем, только если список напоминаний не передан
+	if len(e.Reminders) == 0 && pb.GetNotifyBefore() != nil {
+		e.Reminders = []storage.Reminder{{
+			Offset:  pb.GetNotifyBefore().AsDuration(),
+			Channel: storage.ChannelLog,
+		}}
 	}
 
 	return e, nil
@@ -159,8 +376,15 @@ func domainEventToProto(e storage.Event) *event.Event {
 		pb.Duration = durationpb.New(e.Duration)
 	}
 
-	if e.NotifyBefore != 0 {
-		pb.NotifyBefore = durationpb.New(e.NotifyBefore)
+	if len(e.Reminders) > 0 {
+		pb.NotifyBefore = durationpb.New(e.NotifyBefore())
+	}
+
+	for _, r := range e.Reminders {
+		pb.Reminders = append(pb.Reminders, &event.Reminder{
+			Offset:  durationpb.New(r.Offset),
+			Channel: r.Channel,
+		})
 	}
 
 	return pb
@@ -2,14 +2,22 @@ package grpcserver
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/api/event"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/ratelimit"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
 	memorystorage "github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage/memory"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -464,3 +472,167 @@ func TestGRPCUpdateEventNotFound(t *testing.T) {
 		t.Fatalf("expected NotFound error, got %v", err)
 	}
 }
+
+func TestGRPCEventReminders(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18081)
+
+	now := time.Now()
+	createReq := &event.CreateEventRequest{
+		Event: &event.Event{
+			Id:    "grpc-reminders-1",
+			Title: "Reminders",
+			At:    timestamppb.New(now),
+			Reminders: []*event.Reminder{
+				{Offset: durationpb.New(10 * time.Minute), Channel: "log"},
+				{Offset: durationpb.New(time.Hour), Channel: "webhook"},
+			},
+		},
+	}
+	if _, err := server.CreateEvent(context.Background(), createReq); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	resp, err := server.GetEvent(context.Background(), &event.GetEventRequest{Id: "grpc-reminders-1"})
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if len(resp.Event.Reminders) != 2 {
+		t.Fatalf("expected 2 reminders, got %d", len(resp.Event.Reminders))
+	}
+	if resp.Event.Reminders[1].Channel != "webhook" {
+		t.Fatalf("expected channel 'webhook', got '%s'", resp.Event.Reminders[1].Channel)
+	}
+	// notify_before - синоним первого напоминания
+	if resp.Event.NotifyBefore.AsDuration() != 10*time.Minute {
+		t.Fatalf("expected notify_before 10m, got %v", resp.Event.NotifyBefore.AsDuration())
+	}
+}
+
+func TestGRPCEventInvalidReminderChannel(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18081)
+
+	req := &event.CreateEventRequest{
+		Event: &event.Event{
+			Id:        "grpc-bad-channel",
+			Title:     "Bad",
+			At:        timestamppb.Now(),
+			Reminders: []*event.Reminder{{Offset: durationpb.New(time.Minute), Channel: "pigeon"}},
+		},
+	}
+	_, err := server.CreateEvent(context.Background(), req)
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument error, got %v", err)
+	}
+}
+
+func TestGRPCRequestIDInterceptor(t *testing.T) {
+	interceptor := requestIDInterceptor()
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return logger.RequestIDFromContext(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "req-42"))
+	got, _ := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/event.EventService/GetEvent"}, handler)
+	if got != "req-42" {
+		t.Fatalf("expected request id from metadata, got %v", got)
+	}
+
+	got, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if id, _ := got.(string); id == "" {
+		t.Fatal("expected generated request id")
+	}
+}
+
+type keyAuth map[string]string
+
+func (k keyAuth) Authenticate(credential string) (string, error) {
+	if sub, ok := k[credential]; ok {
+		return sub, nil
+	}
+	return "", errors.New("invalid credentials")
+}
+
+func TestGRPCAuthInterceptor(t *testing.T) {
+	interceptor := authInterceptor(keyAuth{"key-a": "alice"}, nil, logger.New("error"))
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		sub, _ := auth.SubjectFromContext(ctx)
+		return sub, nil
+	}
+	method := &grpc.UnaryServerInfo{FullMethod: "/event.EventService/GetEvent"}
+
+	_, err := interceptor(context.Background(), nil, method, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without credentials, got %v", err)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer key-a"))
+	got, err := interceptor(ctx, nil, method, handler)
+	if err != nil || got != "alice" {
+		t.Fatalf("expected alice, got %v, %v", got, err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "key-b"))
+	if _, err := interceptor(ctx, nil, method, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated for unknown key, got %v", err)
+	}
+
+	// health доступен без аутентификации
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := interceptor(context.Background(), nil, health, handler); err != nil {
+		t.Fatalf("expected health check without credentials, got %v", err)
+	}
+}
+
+func TestGRPCRateLimitInterceptor(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Enabled: true,
+		Routes:  map[string]ratelimit.Rule{"/event.EventService/CreateEvent": {Rate: 1, Burst: 1}},
+	})
+	interceptor := rateLimitInterceptor(limiter)
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) { return "ok", nil }
+	create := &grpc.UnaryServerInfo{FullMethod: "/event.EventService/CreateEvent"}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}})
+
+	if _, err := interceptor(ctx, nil, create, handler); err != nil {
+		t.Fatalf("expected first call to be allowed, got %v", err)
+	}
+	if _, err := interceptor(ctx, nil, create, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+	// у пользователя своя корзина, независимо от IP
+	userCtx := auth.ContextWithSubject(ctx, "alice")
+	if _, err := interceptor(userCtx, nil, create, handler); err != nil {
+		t.Fatalf("expected authenticated user to have own bucket, got %v", err)
+	}
+	// методы без правила не ограничены (правило по умолчанию без rate)
+	get := &grpc.UnaryServerInfo{FullMethod: "/event.EventService/GetEvent"}
+	if _, err := interceptor(ctx, nil, get, handler); err != nil {
+		t.Fatalf("expected GetEvent to be allowed, got %v", err)
+	}
+}
+
+func TestGRPCAuthFailuresAreRateLimited(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Enabled: true, AuthFailures: ratelimit.Rule{Rate: 0.01, Burst: 1}})
+	interceptor := authInterceptor(keyAuth{"key-a": "alice"}, limiter, logger.New("error"))
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) { return "ok", nil }
+	method := &grpc.UnaryServerInfo{FullMethod: "/event.EventService/GetEvent"}
+	withKey := func(ip byte, key string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, ip), Port: 1234}})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", key))
+	}
+
+	if _, err := interceptor(withKey(1, "guess"), nil, method, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if _, err := interceptor(withKey(1, "key-a"), nil, method, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted after failed attempts, got %v", err)
+	}
+	if _, err := interceptor(withKey(2, "key-a"), nil, method, handler); err != nil {
+		t.Fatalf("expected other ip to authenticate, got %v", err)
+	}
+}
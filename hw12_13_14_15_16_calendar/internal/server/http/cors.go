@@ -0,0 +1,161 @@
+package internalhttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// CORSConfig - правила CORS для запросов из браузера с других origin.
+type CORSConfig struct {
+	Enabled bool
+	// AllowedOrigins - точные origin ("https://app.example.com"), шаблоны поддоменов
+	// ("https://*.example.com") или "*" - любой origin
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders - заголовки ответа, доступные скрипту (например, X-Request-ID, Retry-After)
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge - сколько секунд браузер может кешировать ответ на preflight
+	MaxAge int
+}
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "X-API-Key", "X-Request-ID"}
+)
+
+// CORS - middleware CORS, правила которого можно заменить без перезапуска сервера.
+type CORS struct {
+	policy atomic.Pointer[corsPolicy]
+}
+
+type corsPolicy struct {
+	enabled     bool
+	anyOrigin   bool
+	origins     map[string]bool
+	wildcards   []string
+	methods     string
+	headers     string
+	exposed     string
+	credentials bool
+	maxAge      string
+}
+
+func NewCORS(cfg CORSConfig) *CORS {
+	c := &CORS{}
+	c.Configure(cfg)
+	return c
+}
+
+// Configure заменяет правила, например при перезагрузке конфигурации.
+func (c *CORS) Configure(cfg CORSConfig) {
+	p := &corsPolicy{
+		enabled:     cfg.Enabled,
+		origins:     make(map[string]bool),
+		credentials: cfg.AllowCredentials,
+	}
+	for _, o := range cfg.AllowedOrigins {
+		o = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(o)), "/")
+		switch {
+		case o == "*":
+			p.anyOrigin = true
+		case strings.Contains(o, "://*."):
+			// "https://*.example.com" -> схема и суффикс ".example.com"
+			p.wildcards = append(p.wildcards, strings.Replace(o, "://*.", "://.", 1))
+		case o != "":
+			p.origins[o] = true
+		}
+	}
+	methods, headers := cfg.AllowedMethods, cfg.AllowedHeaders
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	p.methods = strings.ToUpper(strings.Join(methods, ", "))
+	p.headers = strings.Join(headers, ", ")
+	p.exposed = strings.Join(cfg.ExposedHeaders, ", ")
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+	c.policy.Store(p)
+}
+
+func (p *corsPolicy) allowed(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+	for _, w := range p.wildcards {
+		scheme, suffix, _ := strings.Cut(w, "://")
+		host, ok := strings.CutPrefix(origin, scheme+"://")
+		if ok && strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware добавляет заголовки CORS к ответам на запросы с разрешённых origin и сам
+// отвечает на preflight (OPTIONS с Access-Control-Request-Method), не передавая его дальше:
+// preflight приходит без учётных данных и не должен упираться в аутентификацию.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := c.policy.Load()
+		origin := r.Header.Get("Origin")
+		if !p.enabled || origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !p.allowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			// браузер сам не отдаст ответ скрипту без Access-Control-Allow-Origin
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// с учётными данными браузер не принимает "*", поэтому origin возвращается как есть
+		if p.anyOrigin && !p.credentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if p.credentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if p.exposed != "" {
+				h.Set("Access-Control-Expose-Headers", p.exposed)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.Set("Access-Control-Allow-Methods", p.methods)
+		h.Set("Access-Control-Allow-Headers", p.headers)
+		if p.maxAge != "" {
+			h.Set("Access-Control-Max-Age", p.maxAge)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,97 @@
+package internalhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	cors := NewCORS(CORSConfig{
+		Enabled:        true,
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		MaxAge:         600,
+	})
+	// preflight не требует учётных данных, даже если API их требует
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0,
+		WithCORS(cors), WithAuth(keyAuth{"key-a": "alice"}))
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/api/events", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, origin := range []string{"https://app.example.com", "https://web.example.org"} {
+		w := preflight(origin)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d", origin, w.Code)
+		}
+		h := w.Header()
+		if h.Get("Access-Control-Allow-Origin") != origin ||
+			h.Get("Access-Control-Allow-Methods") != "GET, POST, PUT, DELETE" ||
+			h.Get("Access-Control-Allow-Headers") == "" ||
+			h.Get("Access-Control-Max-Age") != "600" {
+			t.Fatalf("%s: unexpected preflight headers %v", origin, h)
+		}
+	}
+
+	for _, origin := range []string{"https://evil.com", "http://app.example.com", "https://example.org"} {
+		w := preflight(origin)
+		if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("%s: expected 403 without CORS headers, got %d %v", origin, w.Code, w.Header())
+		}
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	cors := NewCORS(CORSConfig{
+		Enabled:        true,
+		AllowedOrigins: []string{"*"},
+		ExposedHeaders: []string{"X-Request-ID"},
+	})
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0, WithCORS(cors))
+
+	do := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("https://any.site")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "*" ||
+		w.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+		t.Fatalf("unexpected response %d %v", w.Code, w.Header())
+	}
+	if w := do(""); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("expected no CORS headers for same-origin request")
+	}
+
+	// с учётными данными origin возвращается явно
+	cors.Configure(CORSConfig{Enabled: true, AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true})
+	w = do("https://app.example.com")
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("unexpected headers %v", w.Header())
+	}
+	// запрещённый origin обслуживается, но без разрешающих заголовков
+	if w := do("https://other.site"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected response without CORS headers, got %d %v", w.Code, w.Header())
+	}
+
+	// выключение перезагрузкой
+	cors.Configure(CORSConfig{AllowedOrigins: []string{"*"}})
+	if w := do("https://app.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatal("expected CORS to be disabled")
+	}
+}
@@ -0,0 +1,152 @@
+package internalhttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// defaultGzipMinSize - ответы меньше этого размера не сжимаются: выигрыш меньше накладных расходов.
+const defaultGzipMinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() any {
+		gz, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return gz
+	},
+}
+
+// gzipMiddleware сжимает ответы клиентам, приславшим Accept-Encoding: gzip. Ответ копится
+// до minSize байт: короткие ответы уходят как есть, длинные - сжатыми. Ответы, уже
+// имеющие Content-Encoding (например, /metrics), не трогаются.
+func gzipMiddleware(next http.Handler, minSize int) http.Handler {
+	if minSize <= 0 {
+		minSize = defaultGzipMinSize
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip разбирает Accept-Encoding с учётом q=0 ("gzip;q=0" - сжатие запрещено).
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		q := strings.ReplaceAll(strings.ToLower(params), " ", "")
+		return q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+
+	status  int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	decided bool // сжимать или нет уже решено, заголовки отправлены
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.status == 0 {
+		g.status = status
+	}
+	// у ответов без тела и информационных нечего сжимать
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		g.start(false)
+	}
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	if !g.decided {
+		if g.Header().Get("Content-Encoding") != "" {
+			g.start(false)
+		} else {
+			g.buf.Write(b)
+			if g.buf.Len() < g.minSize {
+				return len(b), nil
+			}
+			if err := g.start(true); err != nil {
+				return 0, err
+			}
+			return len(b), nil
+		}
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+	return g.ResponseWriter.Write(b)
+}
+
+// start отправляет заголовки и накопленный буфер, сжатый или как есть.
+func (g *gzipResponseWriter) start(compress bool) error {
+	if g.decided {
+		return nil
+	}
+	g.decided = true
+	if g.status == 0 {
+		g.status = http.StatusOK
+	}
+	if compress {
+		h := g.Header()
+		if h.Get("Content-Type") == "" {
+			// иначе http определит тип по сжатым байтам
+			h.Set("Content-Type", http.DetectContentType(g.buf.Bytes()))
+		}
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		g.gz = gzipWriters.Get().(*gzip.Writer)
+		g.gz.Reset(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(g.status)
+	if g.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if g.gz != nil {
+		_, err = g.gz.Write(g.buf.Bytes())
+	} else {
+		_, err = g.ResponseWriter.Write(g.buf.Bytes())
+	}
+	g.buf.Reset()
+	return err
+}
+
+// Close дописывает короткий ответ без сжатия или завершает поток gzip.
+func (g *gzipResponseWriter) Close() {
+	if !g.decided {
+		if g.status == 0 && g.buf.Len() == 0 {
+			// обработчик ничего не записал - ответ 200 без тела отправит http.Server
+			return
+		}
+		_ = g.start(false)
+		return
+	}
+	if g.gz != nil {
+		_ = g.gz.Close()
+		gzipWriters.Put(g.gz)
+		g.gz = nil
+	}
+}
+
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
@@ -0,0 +1,90 @@
+package internalhttp
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	long := strings.Repeat(`{"title":"meeting"}`, 200)
+	h := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/long":
+			w.Header().Set("Content-Type", "application/json")
+			// ответ частями: решение о сжатии принимается по накопленному размеру
+			for i := 0; i < len(long); i += 100 {
+				_, _ = io.WriteString(w, long[i:min(i+100, len(long))])
+			}
+		case "/short":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"id":"1"}`)
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "gzip")
+			_, _ = io.WriteString(w, long)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}), 1024)
+
+	do := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/long", "deflate, gzip")
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected gzip json response, got %v", w.Header())
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil || string(body) != long {
+		t.Fatalf("unexpected decompressed body (%d bytes), %v", len(body), err)
+	}
+
+	if w := do("/long", ""); w.Header().Get("Content-Encoding") != "" || w.Body.String() != long {
+		t.Fatal("expected uncompressed response without Accept-Encoding")
+	}
+	if w := do("/long", "gzip;q=0"); w.Header().Get("Content-Encoding") != "" {
+		t.Fatal("expected gzip;q=0 to disable compression")
+	}
+
+	w = do("/short", "gzip")
+	if w.Code != http.StatusCreated || w.Header().Get("Content-Encoding") != "" || w.Body.String() != `{"id":"1"}` {
+		t.Fatalf("expected short response as is, got %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+	if w := do("/encoded", "gzip"); w.Body.String() != long {
+		t.Fatal("expected already encoded response not to be compressed twice")
+	}
+	if w := do("/empty", "gzip"); w.Code != http.StatusNoContent || w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("unexpected response without body %d %v", w.Code, w.Header())
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, want := range map[string]bool{
+		"":                  false,
+		"gzip":              true,
+		"GZIP":              true,
+		"br, gzip;q=0.8":    true,
+		"*":                 true,
+		"gzip;q=0":          false,
+		"deflate, identity": false,
+	} {
+		if got := acceptsGzip(header); got != want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
@@ -6,41 +6,30 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
 )
 
-type createEventRequest struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	At           string `json:"at"` // RFC3339 format
-	Duration     string `json:"duration"` // Go duration format (e.g., "1h30m")
-	Description  string `json:"description"`
-	UserID       string `json:"user_id"`
-	NotifyBefore string `json:"notify_before"` // Go duration format
-}
-
-type updateEventRequest struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	At           string `json:"at"` // RFC3339 format
-	Duration     string `json:"duration"` // Go duration format
-	Description  string `json:"description"`
-	UserID       string `json:"user_id"`
-	NotifyBefore string `json:"notify_before"` // Go duration format
+type eventResponse struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	At           string         `json:"at"`       // RFC3339 format
+	Duration     string         `json:"duration"` // Go duration format
+	Description  string         `json:"description"`
+	UserID       string         `json:"user_id"`
+	NotifyBefore string         `json:"notify_before"` // Go duration format, синоним первого напоминания
+	Reminders    []reminderJSON `json:"reminders,omitempty"`
 }
 
-type eventResponse struct {
-	ID           string `json:"id"`
-	Title        string `json:"title"`
-	At           string `json:"at"` // RFC3339 format
-	Duration     string `json:"duration"` // Go duration format
-	Description  string `json:"description"`
-	UserID       string `json:"user_id"`
-	NotifyBefore string `json:"notify_before"` // Go duration format
+type reminderJSON struct {
+	Offset  string `json:"offset"`  // Go duration format
+	Channel string `json:"channel"` // log, email, webhook
 }
 
 type errorResponse struct {
-	Error string `json:"error"`
+	Error     string       `json:"error"`
+	Errors    []fieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
 }
 
 func domainEventToResponse(e storage.Event) eventResponse {
@@ -57,8 +46,14 @@ func domainEventToResponse(e storage.Event) eventResponse {
 	if e.Duration != 0 {
 		resp.Duration = e.Duration.String()
 	}
-	if e.NotifyBefore != 0 {
-		resp.NotifyBefore = e.NotifyBefore.String()
+	if len(e.Reminders) > 0 {
+		resp.NotifyBefore = e.NotifyBefore().String()
+	}
+	for _, r := range e.Reminders {
+		resp.Reminders = append(resp.Reminders, reminderJSON{
+			Offset:  r.Offset.String(),
+			Channel: r.Channel,
+		})
 	}
 
 	return resp
@@ -70,50 +65,22 @@ func (s *Server) createEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req createEventRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+	var req eventRequest
+	if !s.readJSON(w, r, &req) {
 		return
 	}
-
-	at, err := time.Parse(time.RFC3339, req.At)
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid at format. Use RFC3339 format")
+	event, errs := req.event()
+	if len(errs) > 0 {
+		respondValidation(w, errs)
 		return
 	}
 
-	var duration, notifyBefore time.Duration
-	if req.Duration != "" {
-		duration, err = time.ParseDuration(req.Duration)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid duration format. Use Go duration format (e.g., '1h30m')")
-			return
-		}
-	}
-	if req.NotifyBefore != "" {
-		notifyBefore, err = time.ParseDuration(req.NotifyBefore)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid notify_before format. Use Go duration format")
-			return
-		}
-	}
-
-	event := storage.Event{
-		ID:           req.ID,
-		Title:        req.Title,
-		At:           at,
-		Duration:     duration,
-		Description:  req.Description,
-		UserID:       req.UserID,
-		NotifyBefore: notifyBefore,
-	}
-
 	if err := s.app.CreateEvent(r.Context(), event); err != nil {
 		if errors.Is(err, storage.ErrDateBusy) {
 			respondError(w, http.StatusConflict, "Event with this ID already exists")
 			return
 		}
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -126,50 +93,22 @@ func (s *Server) updateEventHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req updateEventRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+	var req eventRequest
+	if !s.readJSON(w, r, &req) {
 		return
 	}
-
-	at, err := time.Parse(time.RFC3339, req.At)
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid at format. Use RFC3339 format")
+	event, errs := req.event()
+	if len(errs) > 0 {
+		respondValidation(w, errs)
 		return
 	}
 
-	var duration, notifyBefore time.Duration
-	if req.Duration != "" {
-		duration, err = time.ParseDuration(req.Duration)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid duration format. Use Go duration format")
-			return
-		}
-	}
-	if req.NotifyBefore != "" {
-		notifyBefore, err = time.ParseDuration(req.NotifyBefore)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid notify_before format. Use Go duration format")
-			return
-		}
-	}
-
-	event := storage.Event{
-		ID:           req.ID,
-		Title:        req.Title,
-		At:           at,
-		Duration:     duration,
-		Description:  req.Description,
-		UserID:       req.UserID,
-		NotifyBefore: notifyBefore,
-	}
-
 	if err := s.app.UpdateEvent(r.Context(), event); err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			respondError(w, http.StatusNotFound, "Event not found")
 			return
 		}
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -193,7 +132,7 @@ func (s *Server) deleteEventHandler(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "Event not found")
 			return
 		}
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -218,7 +157,7 @@ func (s *Server) getEventHandler(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "Event not found")
 			return
 		}
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -233,7 +172,7 @@ func (s *Server) listEventsHandler(w http.ResponseWriter, r *http.Request) {
 
 	events, err := s.app.ListEvents(r.Context())
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -265,7 +204,7 @@ func (s *Server) listEventsDayHandler(w http.ResponseWriter, r *http.Request) {
 
 	events, err := s.app.ListEventsDay(r.Context(), dayStart)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -297,7 +236,7 @@ func (s *Server) listEventsWeekHandler(w http.ResponseWriter, r *http.Request) {
 
 	events, err := s.app.ListEventsWeek(r.Context(), weekStart)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -329,7 +268,7 @@ func (s *Server) listEventsMonthHandler(w http.ResponseWriter, r *http.Request)
 
 	events, err := s.app.ListEventsMonth(r.Context(), monthStart)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		s.internalError(w, r, err)
 		return
 	}
 
@@ -348,6 +287,12 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 }
 
 func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, errorResponse{Error: message})
+	// идентификатор уже выставлен requestIDMiddleware в заголовках ответа
+	respondJSON(w, status, errorResponse{Error: message, RequestID: w.Header().Get(requestid.Header)})
 }
 
+// internalError логирует непредвиденную ошибку вместе с идентификатором запроса и отвечает 500.
+func (s *Server) internalError(w http.ResponseWriter, r *http.Request, err error) {
+	s.logger.ErrorContext(r.Context(), "request failed", "method", r.Method, "path", r.URL.Path, "err", err)
+	respondError(w, http.StatusInternalServerError, err.Error())
+}
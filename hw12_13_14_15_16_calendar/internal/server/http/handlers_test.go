@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -419,3 +420,216 @@ func TestCreateEventHandlerInvalidTime(t *testing.T) {
 		t.Fatalf("expected status 400 for invalid time, got %d", w.Code)
 	}
 }
+
+func TestCreateEventHandlerReminders(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18080)
+
+	eventData := map[string]interface{}{
+		"id":    "reminders-1",
+		"title": "Test",
+		"at":    time.Now().Format(time.RFC3339),
+		"reminders": []map[string]string{
+			{"offset": "10m", "channel": "log"},
+			{"offset": "24h", "channel": "email"},
+		},
+	}
+
+	body, _ := json.Marshal(eventData)
+	req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.createEventHandler(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", w.Code)
+	}
+
+	getReq := httptest.NewRequest(http.MethodGet, "/api/events/get?id=reminders-1", nil)
+	getW := httptest.NewRecorder()
+	server.getEventHandler(getW, getReq)
+
+	var resp eventResponse
+	if err := json.Unmarshal(getW.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Reminders) != 2 {
+		t.Fatalf("expected 2 reminders, got %d", len(resp.Reminders))
+	}
+	if resp.Reminders[1].Channel != storage.ChannelEmail || resp.Reminders[1].Offset != "24h0m0s" {
+		t.Fatalf("unexpected second reminder: %+v", resp.Reminders[1])
+	}
+	// notify_before - синоним первого напоминания
+	if resp.NotifyBefore != "10m0s" {
+		t.Fatalf("expected notify_before '10m0s', got '%s'", resp.NotifyBefore)
+	}
+}
+
+func TestCreateEventHandlerNotifyBeforeAlias(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18080)
+
+	eventData := map[string]interface{}{
+		"id":            "alias-1",
+		"title":         "Test",
+		"at":            time.Now().Format(time.RFC3339),
+		"notify_before": "15m",
+	}
+
+	body, _ := json.Marshal(eventData)
+	req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.createEventHandler(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", w.Code)
+	}
+
+	e, err := app.GetEvent(context.Background(), "alias-1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if len(e.Reminders) != 1 || e.Reminders[0].Offset != 15*time.Minute || e.Reminders[0].Channel != storage.ChannelLog {
+		t.Fatalf("expected single log reminder 15m, got %+v", e.Reminders)
+	}
+}
+
+func TestCreateEventHandlerInvalidReminderChannel(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18080)
+
+	eventData := map[string]interface{}{
+		"id":        "bad-channel-1",
+		"title":     "Test",
+		"at":        time.Now().Format(time.RFC3339),
+		"reminders": []map[string]string{{"offset": "10m", "channel": "pigeon"}},
+	}
+
+	body, _ := json.Marshal(eventData)
+	req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.createEventHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown channel, got %d", w.Code)
+	}
+}
+
+func TestEventHandlersValidation(t *testing.T) {
+	logg := logger.New("debug")
+	app := newMockApp()
+	server := NewServer(logg, app, "127.0.0.1", 18080)
+	_ = app.CreateEvent(context.Background(), storage.Event{ID: "valid-1", Title: "Original", At: time.Now()})
+
+	eventData := map[string]interface{}{
+		"id":            "valid-1",
+		"title":         "  ",
+		"at":            "tomorrow",
+		"duration":      "-1h",
+		"notify_before": "1000h",
+	}
+	body, _ := json.Marshal(eventData)
+
+	// создание и изменение проверяют поля одинаково
+	for _, handler := range []struct {
+		method string
+		serve  http.HandlerFunc
+	}{
+		{http.MethodPost, server.createEventHandler},
+		{http.MethodPut, server.updateEventHandler},
+	} {
+		req := httptest.NewRequest(handler.method, "/api/events", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.serve(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", handler.method, w.Code)
+		}
+		var resp errorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		fields := map[string]bool{}
+		for _, e := range resp.Errors {
+			fields[e.Field] = true
+		}
+		for _, f := range []string{"title", "at", "duration", "notify_before"} {
+			if !fields[f] {
+				t.Errorf("%s: expected error for %s, got %+v", handler.method, f, resp.Errors)
+			}
+		}
+	}
+
+	if e, _ := app.GetEvent(context.Background(), "valid-1"); e.Title != "Original" {
+		t.Fatal("expected event not to be updated by invalid request")
+	}
+}
+
+func TestEventHandlersReminderValidation(t *testing.T) {
+	server := NewServer(logger.New("debug"), newMockApp(), "127.0.0.1", 18080)
+
+	eventData := map[string]interface{}{
+		"id":    "reminders-invalid-1",
+		"title": "Test",
+		"at":    time.Now().Format(time.RFC3339),
+		"reminders": []map[string]string{
+			{"offset": "10m", "channel": "log"},
+			{"offset": "-5m", "channel": "email"},
+			{"offset": "800h", "channel": "pigeon"},
+		},
+	}
+	body, _ := json.Marshal(eventData)
+	req := httptest.NewRequest(http.MethodPost, "/api/events", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	server.createEventHandler(w, req)
+
+	var resp errorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	want := []string{"reminders[1].offset", "reminders[2].offset", "reminders[2].channel"}
+	if w.Code != http.StatusBadRequest || len(resp.Errors) != len(want) {
+		t.Fatalf("expected 400 with %d errors, got %d %+v", len(want), w.Code, resp.Errors)
+	}
+	for i, f := range want {
+		if resp.Errors[i].Field != f {
+			t.Errorf("expected error %d for %s, got %s", i, f, resp.Errors[i].Field)
+		}
+	}
+}
+
+func TestCreateEventHandlerStrictBody(t *testing.T) {
+	server := NewServer(logger.New("debug"), newMockApp(), "127.0.0.1", 18080, WithMaxBodySize(256))
+	valid := `{"id":"strict-1","title":"Test","at":"2024-01-01T10:00:00Z"}`
+
+	cases := []struct {
+		name, body string
+		want       int
+		field      string
+	}{
+		{"unknown field", `{"id":"strict-1","title":"Test","at":"2024-01-01T10:00:00Z","colour":"red"}`, http.StatusBadRequest, "colour"},
+		{"wrong type", `{"id":"strict-1","title":5,"at":"2024-01-01T10:00:00Z"}`, http.StatusBadRequest, "title"},
+		{"trailing data", valid + `{"id":"strict-2"}`, http.StatusBadRequest, ""},
+		{"empty body", ``, http.StatusBadRequest, ""},
+		{"too large", `{"id":"strict-1","title":"Test","description":"` + strings.Repeat("x", 300) + `"}`,
+			http.StatusRequestEntityTooLarge, ""},
+		{"valid", valid, http.StatusCreated, ""},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(c.body))
+		w := httptest.NewRecorder()
+		server.createEventHandler(w, req)
+
+		if w.Code != c.want {
+			t.Fatalf("%s: expected status %d, got %d: %s", c.name, c.want, w.Code, w.Body.String())
+		}
+		if c.field == "" {
+			continue
+		}
+		var resp errorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if len(resp.Errors) != 1 || resp.Errors[0].Field != c.field {
+			t.Fatalf("%s: expected error for field %s, got %+v", c.name, c.field, resp.Errors)
+		}
+	}
+}
@@ -2,24 +2,96 @@ package internalhttp
 
 import (
 	"fmt"
+	"mime"
 	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/auth"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/ratelimit"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// ipFromRequest - адрес клиента. X-Forwarded-For учитывает realIPMiddleware,
+// подставляя адрес клиента в RemoteAddr только для запросов от доверенных прокси.
 func ipFromRequest(r *http.Request) string {
-	if x := r.Header.Get("X-Forwarded-For"); x != "" {
-		// может содержать несколько ip
-		parts := strings.Split(x, ",")
-		return strings.TrimSpace(parts[0])
-	}
 	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
 		return ip
 	}
 	return r.RemoteAddr
 }
 
+// ParseTrustedProxies разбирает адреса и подсети доверенных прокси ("10.0.0.1", "10.0.0.0/8").
+func ParseTrustedProxies(list []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if p, err := netip.ParsePrefix(s); err == nil {
+			out = append(out, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q", s)
+		}
+		out = append(out, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return out, nil
+}
+
+// realIPMiddleware определяет адрес клиента за доверенными прокси. X-Forwarded-For
+// принимается, только если запрос пришёл от доверенного прокси; список разбирается справа
+// налево до первого недоверенного адреса - его и подставить мог только сам клиент.
+// Без доверенных прокси заголовок игнорируется: иначе клиент мог бы менять адрес в каждом
+// запросе и обходить ограничение частоты.
+func realIPMiddleware(next http.Handler, trusted []netip.Prefix) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, p := range trusted {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remote, err := netip.ParseAddr(ipFromRequest(r))
+		xff := r.Header.Values("X-Forwarded-For")
+		if err != nil || len(xff) == 0 || !isTrusted(remote) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		client := remote
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			client = addr.Unmap()
+			if !isTrusted(client) {
+				break
+			}
+		}
+		r = r.WithContext(r.Context())
+		r.RemoteAddr = net.JoinHostPort(client.String(), "0")
+		next.ServeHTTP(w, r)
+	})
+}
+
 type loggingResponseWriter struct {
 	http.ResponseWriter
 	status int
@@ -61,8 +133,163 @@ func loggingMiddleware(next http.Handler, logger Logger) http.Handler {
 			method + " " + path + " " + proto + " " +
 			fmt.Sprintf("%d", status) + " " +
 			fmt.Sprintf("%d", lrw.size) + " \"" + ua + "\""
+		// request_id и trace_id добавляются полями из контекста запроса
+		logger.InfoContext(r.Context(), msg)
+	})
+}
+
+// metricsMiddleware считает запросы и их длительность по шаблону маршрута.
+// ServeMux проставляет r.Pattern во время обработки, поэтому маршрут известен после next.ServeHTTP.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		lrw := &loggingResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(lrw, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		status := lrw.status
+		if status == 0 {
+			status = 200
+		}
+		metrics.ObserveHTTP(route, r.Method, status, time.Since(start))
+	})
+}
+
+// tracingMiddleware открывает серверный спан, продолжая трассу из заголовка traceparent.
+// Должен быть внешним: остальные middleware получают запрос уже с контекстом спана.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracing.Start(ctx, "HTTP "+r.Method, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("client.address", ipFromRequest(r)),
+				attribute.String("request.id", logger.RequestIDFromContext(r.Context())),
+			))
+		defer span.End()
+
+		lrw := &loggingResponseWriter{ResponseWriter: w}
+		r = r.WithContext(ctx)
+		next.ServeHTTP(lrw, r)
 
-		logger.Info(msg)
+		// маршрут известен только после обработки запроса мультиплексором
+		if r.Pattern != "" {
+			span.SetName(r.Method + " " + r.Pattern)
+			span.SetAttributes(attribute.String("http.route", r.Pattern))
+		}
+		status := lrw.status
+		if status == 0 {
+			status = 200
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// requestIDMiddleware берёт X-Request-ID клиента или генерирует новый, возвращает его
+// в ответе и кладёт в контекст, откуда его берут логи всех слоёв. Там же начинается
+// сессия хранилища: чтения после записи в том же запросе не уходят на реплику.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestid.FromClient(r.Header.Get(requestid.Header))
+		w.Header().Set(requestid.Header, id)
+		ctx := storage.WithSession(logger.ContextWithRequestID(r.Context(), id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticate пропускает запрос с действительным токеном (Authorization: Bearer) или API ключом
+// (X-API-Key) и кладёт субъект в контекст. Без настроенной аутентификации пропускает всё.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.auth == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// подбор учётных данных ограничивается по IP до их проверки
+		ipClient := ratelimit.IPClient(ipFromRequest(r))
+		if s.limiter != nil {
+			if ok, wait := s.limiter.AuthAllowed(ipClient); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(wait)))
+				respondError(w, http.StatusTooManyRequests, "too many failed authentication attempts")
+				return
+			}
+		}
+		subject, err := s.auth.Authenticate(auth.Credential(r.Header.Get("Authorization"), r.Header.Get("X-API-Key")))
+		if err != nil {
+			if s.limiter != nil {
+				s.limiter.AuthFailed(ipClient)
+			}
+			s.logger.InfoContext(r.Context(), "authentication failed", "path", r.URL.Path, "err", err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="calendar"`)
+			respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithSubject(r.Context(), subject)))
+	})
+}
+
+// rateLimit отвечает 429 с Retry-After, если клиент исчерпал запросы к маршруту. Клиент -
+// аутентифицированный пользователь, без аутентификации - IP адрес. Правило ищется
+// по "METHOD pattern", затем по pattern.
+func (s *Server) rateLimit(pattern string, next http.Handler) http.Handler {
+	if s.limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := ratelimit.Client(r.Context(), ipFromRequest(r))
+		ok, wait := s.limiter.Allow(client, r.Method+" "+pattern, pattern)
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(wait)))
+			respondError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// negotiate проверяет, что API может понять запрос и ответить в формате, который примет клиент:
+// тело запроса - JSON (415 иначе; запрос без Content-Type считается JSON), а Accept,
+// если задан, допускает application/json (406 иначе).
+func negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "" && !acceptsJSON(accept) {
+			respondError(w, http.StatusNotAcceptable, "only application/json responses are supported")
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "" && r.ContentLength != 0 && !isJSON(ct) {
+			respondError(w, http.StatusUnsupportedMediaType, "request body must be application/json")
+			return
+		}
+		next.ServeHTTP(w, r)
 	})
 }
 
+func isJSON(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mt == "application/json" || strings.HasSuffix(mt, "+json"))
+}
+
+// acceptsJSON разбирает Accept: подходят application/json, application/* и */*, если у них не q=0.
+func acceptsJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		if mt == "application/json" || mt == "application/*" || mt == "*/*" || strings.HasSuffix(mt, "+json") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package internalhttp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/metrics"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/ratelimit"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events/get?id=missing", nil)
+	srv.httpSrv.Handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// метрики отдаёт служебный сервер, в публичном API их нет
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	w := httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "calendar_http_requests_total") {
+		t.Fatal("expected /metrics not to be served on the public port")
+	}
+
+	w = httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	// в метках маршрут, а не путь с параметрами
+	want := `calendar_http_requests_total{code="404",method="GET",route="/api/events/get"}`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Fatalf("expected %s in metrics output", want)
+	}
+}
+
+func TestTracingMiddlewareContinuesTrace(t *testing.T) {
+	if _, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone}); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	var got string
+	handler := tracingMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = tracing.TraceID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected trace id from traceparent, got %q", got)
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	srv := NewServer(logger.New("info", logger.WithOutput(buf)), newMockApp(), "127.0.0.1", 0)
+
+	// идентификатор клиента возвращается в заголовке, теле ошибки и попадает в лог
+	req := httptest.NewRequest(http.MethodGet, "/api/events/get", nil)
+	req.Header.Set(requestid.Header, "req-42")
+	w := httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get(requestid.Header); got != "req-42" {
+		t.Fatalf("expected request id to be echoed, got %q", got)
+	}
+	var resp errorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.RequestID != "req-42" {
+		t.Fatalf("expected request id in error response, got %+v", resp)
+	}
+	if !strings.Contains(buf.String(), "request_id=req-42") {
+		t.Fatalf("expected request id in access log, got %s", buf.String())
+	}
+
+	// без заголовка идентификатор генерируется
+	w = httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/hello", nil))
+	if w.Header().Get(requestid.Header) == "" {
+		t.Fatal("expected generated request id")
+	}
+}
+
+type keyAuth map[string]string
+
+func (k keyAuth) Authenticate(credential string) (string, error) {
+	if sub, ok := k[credential]; ok {
+		return sub, nil
+	}
+	return "", errors.New("invalid credentials")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	app := newMockApp()
+	srv := NewServer(logger.New("error"), app, "127.0.0.1", 0, WithAuth(keyAuth{"key-a": "alice"}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	w := httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("expected 401 with WWW-Authenticate, got %d", w.Code)
+	}
+
+	for _, set := range []func(*http.Request){
+		func(r *http.Request) { r.Header.Set("Authorization", "Bearer key-a") },
+		func(r *http.Request) { r.Header.Set("X-API-Key", "key-a") },
+	} {
+		req = httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		set(req)
+		w = httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200 with valid credentials, got %d", w.Code)
+		}
+	}
+
+	// маршруты вне /api доступны без аутентификации
+	req = httptest.NewRequest(http.MethodGet, "/hello", nil)
+	w = httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected /hello without credentials, got %d", w.Code)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{Enabled: true, Default: ratelimit.Rule{Rate: 0.5, Burst: 1}})
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0, WithRateLimit(limiter))
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("10.0.0.1:1234"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	w := do("10.0.0.1:1235")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Fatalf("expected 429 with Retry-After: 2, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	// другой IP ограничивается отдельно
+	if w := do("10.0.0.2:1234"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other client, got %d", w.Code)
+	}
+	// маршруты вне /api не ограничиваются
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected /hello not to be limited, got %d", w.Code)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0)
+	body := `{"id":"n1","title":"Meeting","at":"2024-01-01T10:00:00Z"}`
+
+	cases := []struct {
+		name, contentType, accept string
+		want                      int
+	}{
+		{"json", "application/json; charset=utf-8", "application/json", http.StatusCreated},
+		{"no headers", "", "", http.StatusCreated},
+		{"any accept", "application/json", "text/html, */*;q=0.8", http.StatusCreated},
+		{"form body", "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"xml accept", "application/json", "application/xml", http.StatusNotAcceptable},
+		{"json refused", "application/json", "application/json;q=0, text/plain", http.StatusNotAcceptable},
+	}
+	for i, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(strings.Replace(body, "n1", "n1"+c.name, 1)))
+		if c.contentType != "" {
+			req.Header.Set("Content-Type", c.contentType)
+		}
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		if w.Code != c.want {
+			t.Errorf("case %d %s: expected %d, got %d: %s", i, c.name, c.want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAuthFailuresAreRateLimited(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Config{
+		Enabled:      true,
+		AuthFailures: ratelimit.Rule{Rate: 0.01, Burst: 2},
+	})
+	srv := NewServer(logger.New("error"), newMockApp(), "127.0.0.1", 0,
+		WithAuth(keyAuth{"key-a": "alice"}), WithRateLimit(limiter))
+
+	do := func(remoteAddr, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		srv.httpSrv.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := do("10.0.0.1:1234", "guess"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401, got %d", w.Code)
+		}
+	}
+	// после исчерпания попыток ключи с этого IP не проверяются, даже верные
+	w := do("10.0.0.1:1234", "key-a")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 429 with Retry-After, got %d", w.Code)
+	}
+	// подделанный X-Forwarded-For не даёт новую корзину
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "192.0.2.77")
+	req.Header.Set("X-API-Key", "guess")
+	w = httptest.NewRecorder()
+	srv.httpSrv.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected spoofed X-Forwarded-For to be ignored, got %d", w.Code)
+	}
+	if w := do("10.0.0.2:1234", "key-a"); w.Code != http.StatusOK {
+		t.Fatalf("expected other ip to authenticate, got %d", w.Code)
+	}
+}
+
+func TestRealIPMiddleware(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	h := realIPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = ipFromRequest(r)
+	}), proxies)
+
+	cases := []struct {
+		remote, xff, want string
+	}{
+		// клиент напрямую: заголовок игнорируется
+		{"203.0.113.5:1000", "1.2.3.4", "203.0.113.5"},
+		// через доверенный прокси
+		{"10.1.1.1:1000", "203.0.113.5", "203.0.113.5"},
+		// клиент подставил свой адрес слева - берётся первый недоверенный справа
+		{"10.1.1.1:1000", "1.2.3.4, 203.0.113.5, 192.168.1.1", "203.0.113.5"},
+		// только доверенные адреса
+		{"10.1.1.1:1000", "10.2.2.2", "10.2.2.2"},
+		// мусор в заголовке
+		{"10.1.1.1:1000", "not-an-ip", "10.1.1.1"},
+		{"10.1.1.1:1000", "", "10.1.1.1"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if got != c.want {
+			t.Errorf("remote %s, X-Forwarded-For %q: got %s, want %s", c.remote, c.xff, got, c.want)
+		}
+	}
+
+	if _, err := ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Fatal("expected error for invalid proxy address")
+	}
+}
@@ -0,0 +1,195 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/requestid"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+)
+
+const (
+	// defaultMaxBodySize - ограничение размера тела запроса, если не задано WithMaxBodySize.
+	defaultMaxBodySize = 1 << 20
+	// maxNotifyBefore - за сколько до события можно напомнить самое раннее.
+	maxNotifyBefore = 30 * 24 * time.Hour
+)
+
+// eventRequest - тело запросов на создание и изменение события.
+type eventRequest struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	At           string         `json:"at"`       // RFC3339 format
+	Duration     string         `json:"duration"` // Go duration format (e.g., "1h30m")
+	Description  string         `json:"description"`
+	UserID       string         `json:"user_id"`
+	NotifyBefore string         `json:"notify_before"` // Go duration format, синоним первого напоминания
+	Reminders    []reminderJSON `json:"reminders,omitempty"`
+}
+
+// fieldError - ошибка в одном поле запроса.
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// readJSON читает тело запроса в dst: не больше maxBody байт, ровно один JSON объект,
+// без неизвестных полей. При ошибке сам отвечает клиенту и возвращает false.
+func (s *Server) readJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBody))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(dst)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("request body must contain a single JSON object")
+	}
+	if err == nil {
+		return true
+	}
+
+	var (
+		tooLarge  *http.MaxBytesError
+		typeError *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		respondError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("request body must not exceed %d bytes", tooLarge.Limit))
+	case errors.As(err, &typeError) && typeError.Field != "":
+		respondValidation(w, []fieldError{{Field: typeError.Field, Message: "must be " + jsonType(typeError.Type.Kind().String())}})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// у encoding/json нет отдельного типа ошибки для неизвестного поля
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		respondValidation(w, []fieldError{{Field: field, Message: "unknown field"}})
+	case errors.Is(err, io.EOF):
+		respondError(w, http.StatusBadRequest, "Invalid request body: empty body")
+	default:
+		respondError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	return false
+}
+
+func jsonType(kind string) string {
+	switch kind {
+	case "string":
+		return "a string"
+	case "slice":
+		return "an array"
+	case "struct", "map":
+		return "an object"
+	default:
+		return "a " + kind
+	}
+}
+
+// event проверяет все поля запроса и собирает событие. Ошибки возвращаются списком,
+// чтобы клиент мог исправить все поля за один раз.
+func (req eventRequest) event() (storage.Event, []fieldError) {
+	var errs []fieldError
+	add := func(field, msg string) {
+		errs = append(errs, fieldError{Field: field, Message: msg})
+	}
+
+	if strings.TrimSpace(req.Title) == "" {
+		add("title", "required")
+	}
+
+	var at time.Time
+	if req.At == "" {
+		add("at", "required")
+	} else if t, err := time.Parse(time.RFC3339, req.At); err != nil {
+		add("at", "invalid format, use RFC3339")
+	} else {
+		at = t
+	}
+
+	var duration time.Duration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		switch {
+		case err != nil:
+			add("duration", "invalid format, use Go duration format (e.g., '1h30m')")
+		case d < 0:
+			add("duration", "must not be negative")
+		default:
+			duration = d
+		}
+	}
+
+	reminders, reminderErrs := parseReminders(req.Reminders, req.NotifyBefore)
+	errs = append(errs, reminderErrs...)
+
+	return storage.Event{
+		ID:          req.ID,
+		Title:       req.Title,
+		At:          at,
+		Duration:    duration,
+		Description: req.Description,
+		UserID:      req.UserID,
+		Reminders:   reminders,
+	}, errs
+}
+
+// parseReminders разбирает список напоминаний из запроса.
+// notify_before используется, только если список напоминаний не передан.
+func parseReminders(items []reminderJSON, notifyBefore string) ([]storage.Reminder, []fieldError) {
+	if len(items) == 0 {
+		if notifyBefore == "" {
+			return nil, nil
+		}
+		d, msg := parseOffset(notifyBefore)
+		if msg != "" {
+			return nil, []fieldError{{Field: "notify_before", Message: msg}}
+		}
+		return []storage.Reminder{{Offset: d, Channel: storage.ChannelLog}}, nil
+	}
+
+	var errs []fieldError
+	out := make([]storage.Reminder, 0, len(items))
+	for i, item := range items {
+		d, msg := parseOffset(item.Offset)
+		if msg != "" {
+			errs = append(errs, fieldError{Field: fmt.Sprintf("reminders[%d].offset", i), Message: msg})
+		}
+		channel, ok := storage.ParseChannel(item.Channel)
+		if !ok {
+			errs = append(errs, fieldError{
+				Field:   fmt.Sprintf("reminders[%d].channel", i),
+				Message: "unknown channel, use one of: log, email, webhook",
+			})
+		}
+		out = append(out, storage.Reminder{Offset: d, Channel: channel})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return out, nil
+}
+
+// parseOffset разбирает время напоминания до события; пустое сообщение - ошибок нет.
+func parseOffset(s string) (time.Duration, string) {
+	d, err := time.ParseDuration(s)
+	switch {
+	case err != nil:
+		return 0, "invalid format, use Go duration format"
+	case d < 0:
+		return 0, "must not be negative"
+	case d > maxNotifyBefore:
+		return 0, "must not exceed " + maxNotifyBefore.String()
+	}
+	return d, ""
+}
+
+// respondValidation отвечает 400 со списком ошибок по полям.
+func respondValidation(w http.ResponseWriter, errs []fieldError) {
+	respondJSON(w, http.StatusBadRequest, errorResponse{
+		Error:     "validation failed",
+		Errors:    errs,
+		RequestID: w.Header().Get(requestid.Header),
+	})
+}
@@ -2,9 +2,11 @@ package internalhttp
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
 	"time"
 
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
@@ -15,13 +17,91 @@ type Server struct {
 	app     Application
 	host    string
 	port    int
+	mux     *http.ServeMux
 	httpSrv *http.Server
+	auth    Authenticator
+	limiter RateLimiter
+	tls     *tls.Config
+	cors    *CORS
+	gzip    int
+	maxBody int64
+	proxies []netip.Prefix
+}
+
+// Authenticator проверяет учётные данные запроса и возвращает субъект.
+type Authenticator interface {
+	Authenticate(credential string) (string, error)
+}
+
+// RateLimiter расходует токен клиента на запрос к маршруту и ограничивает неудачные
+// попытки аутентификации с одного IP (см. ratelimit.Limiter).
+type RateLimiter interface {
+	Allow(client string, routes ...string) (bool, time.Duration)
+	AuthAllowed(client string) (bool, time.Duration)
+	AuthFailed(client string)
+}
+
+type Option func(*Server)
+
+// WithAuth требует аутентификации для всех маршрутов /api/.
+func WithAuth(a Authenticator) Option {
+	return func(s *Server) {
+		s.auth = a
+	}
+}
+
+// WithRateLimit ограничивает частоту запросов к маршрутам /api/.
+func WithRateLimit(l RateLimiter) Option {
+	return func(s *Server) {
+		s.limiter = l
+	}
+}
+
+// WithTLS включает HTTPS. Сертификат берётся из cfg при каждом соединении,
+// поэтому GetCertificate может отдавать перечитанный с диска сертификат.
+func WithTLS(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.tls = cfg
+	}
+}
+
+// WithCORS разрешает вызовы API из браузера с других origin по правилам c.
+func WithCORS(c *CORS) Option {
+	return func(s *Server) {
+		s.cors = c
+	}
+}
+
+// WithGzip сжимает ответы от minSize байт клиентам, принимающим gzip. minSize <= 0 - 1 КБ.
+func WithGzip(minSize int) Option {
+	return func(s *Server) {
+		s.gzip = minSize
+		if minSize <= 0 {
+			s.gzip = defaultGzipMinSize
+		}
+	}
+}
+
+// WithTrustedProxies - прокси, чьему X-Forwarded-For можно верить при определении адреса клиента.
+func WithTrustedProxies(proxies []netip.Prefix) Option {
+	return func(s *Server) {
+		s.proxies = proxies
+	}
+}
+
+// WithMaxBodySize ограничивает размер тела запросов к API; n <= 0 - 1 МБ.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Server) {
+		s.maxBody = n
+	}
 }
 
 type Logger interface {
 	Info(msg string)
 	Error(msg string)
 	Debug(msg string)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
 }
 
 type Application interface {
@@ -35,18 +115,29 @@ type Application interface {
 	ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error)
 }
 
-func NewServer(logger Logger, app Application, host string, port int) *Server {
+func NewServer(logger Logger, app Application, host string, port int, opts ...Option) *Server {
 	s := &Server{
 		logger: logger,
 		app:    app,
 		host:   host,
 		port:   port,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.maxBody <= 0 {
+		s.maxBody = defaultMaxBodySize
+	}
 
 	mux := http.NewServeMux()
+	s.mux = mux
 
-	// API endpoints
-	mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+	// API endpoints; аутентификация и ограничение частоты проверяются внутри mux,
+	// чтобы middleware снаружи видели маршрут запроса (r.Pattern)
+	api := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, s.authenticate(s.rateLimit(pattern, negotiate(h))))
+	}
+	api("/api/events", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
 			s.createEventHandler(w, r)
@@ -57,12 +148,12 @@ func NewServer(logger Logger, app Application, host string, port int) *Server {
 		}
 	})
 
-	mux.HandleFunc("/api/events/update", s.updateEventHandler)
-	mux.HandleFunc("/api/events/delete", s.deleteEventHandler)
-	mux.HandleFunc("/api/events/get", s.getEventHandler)
-	mux.HandleFunc("/api/events/day", s.listEventsDayHandler)
-	mux.HandleFunc("/api/events/week", s.listEventsWeekHandler)
-	mux.HandleFunc("/api/events/month", s.listEventsMonthHandler)
+	api("/api/events/update", s.updateEventHandler)
+	api("/api/events/delete", s.deleteEventHandler)
+	api("/api/events/get", s.getEventHandler)
+	api("/api/events/day", s.listEventsDayHandler)
+	api("/api/events/week", s.listEventsWeekHandler)
+	api("/api/events/month", s.listEventsMonthHandler)
 
 	// Legacy endpoints for backward compatibility
 	mux.HandleFunc("/hello", func(w http.ResponseWriter, _ *http.Request) {
@@ -76,28 +167,52 @@ func NewServer(logger Logger, app Application, host string, port int) *Server {
 	})
 
 	// wrap middleware
-	handler := loggingMiddleware(mux, logger)
+	// tracingMiddleware читает маршрут из запроса, который дошёл до mux,
+	// поэтому между ним и mux не должно быть middleware, копирующих запрос
+	var handler http.Handler = mux
+	if s.gzip > 0 {
+		handler = gzipMiddleware(handler, s.gzip)
+	}
+	if s.cors != nil {
+		// preflight обрабатывается до mux и не доходит до аутентификации
+		handler = s.cors.Middleware(handler)
+	}
+	handler = requestIDMiddleware(tracingMiddleware(loggingMiddleware(metricsMiddleware(handler), logger)))
+	handler = realIPMiddleware(handler, s.proxies)
 
 	s.httpSrv = &http.Server{
 		Handler:      handler,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		TLSConfig:    s.tls,
 	}
 
 	return s
 }
 
+// Handle регистрирует дополнительный обработчик (например, пробы /healthz и /readyz).
+// Вызывать до Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
 func (s *Server) Start(ctx context.Context) error {
 	addr := fmt.Sprintf("%s:%d", s.host, s.port)
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
-	s.logger.Info("http server listening on " + addr)
+	serve := s.httpSrv.Serve
+	if s.httpSrv.TLSConfig != nil {
+		serve = func(ln net.Listener) error { return s.httpSrv.ServeTLS(ln, "", "") }
+		s.logger.Info("https server listening on " + addr)
+	} else {
+		s.logger.Info("http server listening on " + addr)
+	}
 
 	go func() {
-		if err := s.httpSrv.Serve(ln); err != nil && err != http.ErrServerClosed {
+		if err := serve(ln); err != nil && err != http.ErrServerClosed {
 			s.logger.Error("http serve error: " + err.Error())
 		}
 	}()
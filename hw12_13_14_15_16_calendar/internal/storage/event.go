@@ -1,13 +1,50 @@
 package storage
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// Каналы доставки напоминаний.
+const (
+	ChannelLog     = "log"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
 
 type Event struct {
-	ID           string
-	Title        string
-	At           time.Time
-	Duration     time.Duration
-	Description  string
-	UserID       string
-	NotifyBefore time.Duration
+	ID          string
+	Title       string
+	At          time.Time
+	Duration    time.Duration
+	Description string
+	UserID      string
+	Reminders   []Reminder
+}
+
+// Reminder - одно напоминание о событии: за сколько до начала и куда отправлять.
+type Reminder struct {
+	Offset  time.Duration
+	Channel string
+}
+
+// NotifyBefore возвращает смещение первого напоминания.
+// Оставлено для обратной совместимости с полем notify_before.
+func (e Event) NotifyBefore() time.Duration {
+	if len(e.Reminders) == 0 {
+		return 0
+	}
+	return e.Reminders[0].Offset
+}
+
+// ParseChannel проверяет название канала; пустая строка означает ChannelLog.
+func ParseChannel(s string) (string, bool) {
+	switch ch := strings.ToLower(strings.TrimSpace(s)); ch {
+	case "":
+		return ChannelLog, true
+	case ChannelLog, ChannelEmail, ChannelWebhook:
+		return ch, true
+	default:
+		return "", false
+	}
 }
@@ -13,12 +13,44 @@ import (
 type Storage struct {
 	mu     sync.RWMutex
 	events map[string]storage.Event
+	// учёт отправленных напоминаний: ключ уведомления -> запись
+	notifications map[string]notificationRecord
+	// журнал попыток доставки на webhook
+	webhookDeliveries []storage.WebhookDelivery
+	// записи outbox, ожидающие публикации; ведутся только с WithOutbox
+	outboxEnabled bool
+	outbox        []storage.OutboxRecord
+	outboxMu      sync.Mutex // один ProcessOutbox за раз
+	outboxLastID  int64
 }
 
-func New() *Storage {
-	return &Storage{
-		events: make(map[string]storage.Event),
+type Option func(*Storage)
+
+// WithOutbox включает запись изменений событий в outbox. Без релея, который забирает
+// записи (ProcessOutbox), журнал только растёт, поэтому включать
+// его стоит лишь в тестах и там, где релей запущен.
+func WithOutbox() Option {
+	return func(s *Storage) {
+		s.outboxEnabled = true
+	}
+}
+
+type notificationRecord struct {
+	eventID    string
+	status     string
+	enqueuedAt time.Time
+	sendingAt  time.Time
+}
+
+func New(opts ...Option) *Storage {
+	s := &Storage{
+		events:        make(map[string]storage.Event),
+		notifications: make(map[string]notificationRecord),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Storage) CreateEvent(_ context.Context, e storage.Event) error {
@@ -27,7 +59,10 @@ func (s *Storage) CreateEvent(_ context.Context, e storage.Event) error {
 	if _, ok := s.events[e.ID]; ok {
 		return storage.ErrDateBusy
 	}
-	s.events[e.ID] = e
+	if err := s.writeOutbox(storage.EventCreated, e); err != nil {
+		return err
+	}
+	s.events[e.ID] = cloneEvent(e)
 	return nil
 }
 
@@ -37,7 +72,10 @@ func (s *Storage) UpdateEvent(_ context.Context, e storage.Event) error {
 	if _, ok := s.events[e.ID]; !ok {
 		return storage.ErrNotFound
 	}
-	s.events[e.ID] = e
+	if err := s.writeOutbox(storage.EventUpdated, e); err != nil {
+		return err
+	}
+	s.events[e.ID] = cloneEvent(e)
 	return nil
 }
 
@@ -47,7 +85,22 @@ func (s *Storage) DeleteEvent(_ context.Context, id string) error {
 	if _, ok := s.events[id]; !ok {
 		return storage.ErrNotFound
 	}
+	if err := s.writeOutbox(storage.EventDeleted, storage.Event{ID: id}); err != nil {
+		return err
+	}
 	delete(s.events, id)
+	for key, rec := range s.notifications {
+		if rec.eventID == id {
+			delete(s.notifications, key)
+		}
+	}
+	deliveries := s.webhookDeliveries[:0]
+	for _, d := range s.webhookDeliveries {
+		if d.Notification.EventID != id {
+			deliveries = append(deliveries, d)
+		}
+	}
+	s.webhookDeliveries = deliveries
 	return nil
 }
 
@@ -58,7 +111,7 @@ func (s *Storage) GetEvent(_ context.Context, id string) (storage.Event, error)
 	if !ok {
 		return storage.Event{}, storage.ErrNotFound
 	}
-	return e, nil
+	return cloneEvent(e), nil
 }
 
 func (s *Storage) ListEvents(_ context.Context) ([]storage.Event, error) {
@@ -66,7 +119,7 @@ func (s *Storage) ListEvents(_ context.Context) ([]storage.Event, error) {
 	defer s.mu.RUnlock()
 	out := make([]storage.Event, 0, len(s.events))
 	for _, v := range s.events {
-		out = append(out, v)
+		out = append(out, cloneEvent(v))
 	}
 	return out, nil
 }
@@ -79,7 +132,7 @@ func (s *Storage) ListEventsDay(_ context.Context, dayStart time.Time) ([]storag
 	out := []storage.Event{}
 	for _, ev := range s.events {
 		if ev.At.Equal(start) || (ev.At.After(start) && ev.At.Before(end)) {
-			out = append(out, ev)
+			out = append(out, cloneEvent(ev))
 		}
 	}
 	return out, nil
@@ -93,7 +146,7 @@ func (s *Storage) ListEventsWeek(_ context.Context, weekStart time.Time) ([]stor
 	out := []storage.Event{}
 	for _, ev := range s.events {
 		if (ev.At.Equal(start) || ev.At.After(start)) && ev.At.Before(end) {
-			out = append(out, ev)
+			out = append(out, cloneEvent(ev))
 		}
 	}
 	return out, nil
@@ -107,8 +160,187 @@ func (s *Storage) ListEventsMonth(_ context.Context, monthStart time.Time) ([]st
 	out := []storage.Event{}
 	for _, ev := range s.events {
 		if (ev.At.Equal(start) || ev.At.After(start)) && ev.At.Before(end) {
-			out = append(out, ev)
+			out = append(out, cloneEvent(ev))
 		}
 	}
 	return out, nil
 }
+
+func (s *Storage) ClaimNotification(_ context.Context, n storage.Notification, lease time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := n.Key()
+	now := time.Now()
+	// неотправленная отметка старше lease перехватывается заново
+	if rec, ok := s.notifications[key]; ok &&
+		(rec.status != storage.NotificationEnqueued || !rec.enqueuedAt.Before(now.Add(-lease))) {
+		return false, nil
+	}
+	s.notifications[key] = notificationRecord{eventID: n.EventID, status: storage.NotificationEnqueued, enqueuedAt: now}
+	return true, nil
+}
+
+func (s *Storage) ReleaseNotification(_ context.Context, n storage.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := n.Key()
+	if rec, ok := s.notifications[key]; ok && rec.status == storage.NotificationEnqueued {
+		delete(s.notifications, key)
+	}
+	return nil
+}
+
+func (s *Storage) MarkNotificationPublished(_ context.Context, n storage.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := n.Key()
+	if rec, ok := s.notifications[key]; ok && rec.status == storage.NotificationEnqueued {
+		rec.status = storage.NotificationPublished
+		s.notifications[key] = rec
+	}
+	return nil
+}
+
+// ClaimDelivery берёт уведомление на доставку, см. sqlstorage.Storage.ClaimDelivery.
+func (s *Storage) ClaimDelivery(_ context.Context, n storage.Notification, lease time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// в отличие от sql, наличие события не проверяется: рассыльщик с хранилищем в памяти
+	// не видит событий календаря, они живут в другом процессе
+	key := n.Key()
+	now := time.Now()
+	rec, ok := s.notifications[key]
+	if ok && (rec.status == storage.NotificationSent ||
+		rec.status == storage.NotificationSending && !rec.sendingAt.Before(now.Add(-lease))) {
+		return false, nil
+	}
+	rec.eventID = n.EventID
+	rec.status = storage.NotificationSending
+	rec.sendingAt = now
+	s.notifications[key] = rec
+	return true, nil
+}
+
+func (s *Storage) MarkNotificationSent(_ context.Context, n storage.Notification) error {
+	s.finishDelivery(n, storage.NotificationSent)
+	return nil
+}
+
+func (s *Storage) MarkNotificationFailed(_ context.Context, n storage.Notification, dead bool) error {
+	status := storage.NotificationFailed
+	if dead {
+		status = storage.NotificationDead
+	}
+	s.finishDelivery(n, status)
+	return nil
+}
+
+func (s *Storage) finishDelivery(n storage.Notification, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := n.Key()
+	if rec, ok := s.notifications[key]; ok && rec.status == storage.NotificationSending {
+		rec.status = status
+		s.notifications[key] = rec
+	}
+}
+
+func (s *Storage) IsNotificationSent(_ context.Context, n storage.Notification) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rec, ok := s.notifications[n.Key()]
+	return ok && rec.status == storage.NotificationSent, nil
+}
+
+func (s *Storage) RecordWebhookDelivery(_ context.Context, d storage.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// событие могло быть удалено, пока уведомление было в очереди
+	if _, ok := s.events[d.Notification.EventID]; !ok {
+		return nil
+	}
+	s.webhookDeliveries = append(s.webhookDeliveries, d)
+	return nil
+}
+
+func (s *Storage) WebhookDeliveries(_ context.Context, eventID string) ([]storage.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var res []storage.WebhookDelivery
+	for _, d := range s.webhookDeliveries {
+		if d.Notification.EventID == eventID {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
+// writeOutbox добавляет запись outbox; вызывается под блокировкой вместе с изменением события.
+func (s *Storage) writeOutbox(kind string, e storage.Event) error {
+	if !s.outboxEnabled {
+		return nil
+	}
+	rec, err := storage.NewOutboxRecord(kind, e)
+	if err != nil {
+		return err
+	}
+	s.outboxLastID++
+	rec.ID = s.outboxLastID
+	s.outbox = append(s.outbox, rec)
+	return nil
+}
+
+// PendingOutbox возвращает недоставленные записи outbox в порядке их появления.
+func (s *Storage) PendingOutbox(_ context.Context, limit int) ([]storage.OutboxRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	n := len(s.outbox)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	return append([]storage.OutboxRecord(nil), s.outbox[:n]...), nil
+}
+
+// ProcessOutbox передаёт fn пачку недоставленных записей и удаляет записи с ID,
+// которые вернула fn. Вызовы ProcessOutbox выполняются по одному.
+func (s *Storage) ProcessOutbox(ctx context.Context, limit int,
+	fn func(ctx context.Context, records []storage.OutboxRecord) []int64,
+) (int, error) {
+	s.outboxMu.Lock()
+	defer s.outboxMu.Unlock()
+
+	records, _ := s.PendingOutbox(ctx, limit)
+	if len(records) == 0 {
+		return 0, nil
+	}
+	ids := fn(ctx, records)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivered := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		delivered[id] = struct{}{}
+	}
+	pending := s.outbox[:0]
+	for _, rec := range s.outbox {
+		if _, ok := delivered[rec.ID]; !ok {
+			pending = append(pending, rec)
+		}
+	}
+	s.outbox = pending
+	return len(ids), nil
+}
+
+// PurgeOutbox ничего не делает: доставленные записи удаляются сразу в ProcessOutbox.
+func (s *Storage) PurgeOutbox(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// cloneEvent копирует событие вместе со списком напоминаний,
+// чтобы вызывающий код не мог изменить данные хранилища через общий слайс.
+func cloneEvent(e storage.Event) storage.Event {
+	if e.Reminders != nil {
+		e.Reminders = append([]storage.Reminder(nil), e.Reminders...)
+	}
+	return e
+}
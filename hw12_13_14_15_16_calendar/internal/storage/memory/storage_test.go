@@ -131,3 +131,123 @@ func TestStorageBusinessErrors(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, got: %v", err)
 	}
 }
+
+func TestStorageRemindersAreCopied(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	e := storage.Event{
+		ID:    "1",
+		Title: "test",
+		At:    time.Now(),
+		Reminders: []storage.Reminder{
+			{Offset: time.Hour, Channel: storage.ChannelLog},
+			{Offset: 24 * time.Hour, Channel: storage.ChannelEmail},
+		},
+	}
+	if err := s.CreateEvent(ctx, e); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+
+	// изменение исходного слайса не должно влиять на хранилище
+	e.Reminders[0].Offset = time.Minute
+
+	got, err := s.GetEvent(ctx, "1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if len(got.Reminders) != 2 || got.Reminders[0].Offset != time.Hour {
+		t.Fatalf("unexpected reminders: %+v", got.Reminders)
+	}
+	if got.NotifyBefore() != time.Hour {
+		t.Fatalf("expected NotifyBefore=1h, got %v", got.NotifyBefore())
+	}
+}
+
+func TestStorageNotificationBookkeeping(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+	at := time.Now().Add(time.Hour)
+	_ = s.CreateEvent(ctx, storage.Event{ID: "1", Title: "test", At: at})
+
+	n := storage.Notification{EventID: "1", At: at, Offset: time.Hour, Channel: storage.ChannelLog}
+
+	claimed, _ := s.ClaimNotification(ctx, n, time.Hour)
+	if !claimed {
+		t.Fatalf("expected first claim to succeed")
+	}
+	claimed, _ = s.ClaimNotification(ctx, n, time.Hour)
+	if claimed {
+		t.Fatalf("expected second claim to fail")
+	}
+	// отметка старше lease без отправки перехватывается
+	time.Sleep(2 * time.Millisecond)
+	if claimed, _ := s.ClaimNotification(ctx, n, time.Millisecond); !claimed {
+		t.Fatalf("expected expired claim to be taken over")
+	}
+
+	// опубликованное напоминание не перехватывается и после lease
+	_ = s.MarkNotificationPublished(ctx, n)
+	time.Sleep(2 * time.Millisecond)
+	if claimed, _ := s.ClaimNotification(ctx, n, time.Millisecond); claimed {
+		t.Fatalf("published notification must not be claimed again")
+	}
+
+	// доставку берёт только один рассыльщик
+	if claimed, _ := s.ClaimDelivery(ctx, n, time.Hour); !claimed {
+		t.Fatalf("expected delivery claim to succeed")
+	}
+	if claimed, _ := s.ClaimDelivery(ctx, n, time.Hour); claimed {
+		t.Fatalf("expected concurrent delivery claim to fail")
+	}
+	// после неудачи (в том числе в очередь недоставленных) доставку можно взять снова
+	_ = s.MarkNotificationFailed(ctx, n, true)
+	if claimed, _ := s.ClaimDelivery(ctx, n, time.Hour); !claimed {
+		t.Fatalf("expected failed delivery to be claimable")
+	}
+
+	if sent, _ := s.IsNotificationSent(ctx, n); sent {
+		t.Fatalf("notification must not be sent yet")
+	}
+	_ = s.MarkNotificationSent(ctx, n)
+	if sent, _ := s.IsNotificationSent(ctx, n); !sent {
+		t.Fatalf("expected notification to be sent")
+	}
+	time.Sleep(2 * time.Millisecond)
+	if claimed, _ := s.ClaimDelivery(ctx, n, time.Millisecond); claimed {
+		t.Fatalf("sent notification must not be delivered again")
+	}
+
+	// отправленное уведомление нельзя снять с учёта
+	_ = s.ReleaseNotification(ctx, n)
+	time.Sleep(2 * time.Millisecond)
+	if claimed, _ := s.ClaimNotification(ctx, n, time.Millisecond); claimed {
+		t.Fatalf("released sent notification must stay claimed")
+	}
+
+	// удаление события удаляет и учёт его уведомлений
+	_ = s.DeleteEvent(ctx, "1")
+	if sent, _ := s.IsNotificationSent(ctx, n); sent {
+		t.Fatalf("expected bookkeeping to be removed with the event")
+	}
+}
+
+func TestStorageOutboxIsOptIn(t *testing.T) {
+	ctx := context.Background()
+	e := storage.Event{ID: "1", Title: "test", At: time.Now()}
+
+	s := New()
+	_ = s.CreateEvent(ctx, e)
+	_ = s.DeleteEvent(ctx, "1")
+	if recs, _ := s.PendingOutbox(ctx, 0); len(recs) != 0 {
+		t.Fatalf("expected no outbox records without WithOutbox, got %d", len(recs))
+	}
+
+	s = New(WithOutbox())
+	_ = s.CreateEvent(ctx, e)
+	_ = s.DeleteEvent(ctx, "1")
+	recs, _ := s.PendingOutbox(ctx, 0)
+	if len(recs) != 2 || recs[0].Type != storage.EventCreated || recs[1].Type != storage.EventDeleted {
+		t.Fatalf("unexpected outbox records %+v", recs)
+	}
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// Статусы учёта отправки уведомлений. Планировщик переводит напоминание
+// enqueued -> published, рассыльщик - в sending перед доставкой и затем в sent,
+// failed (ждёт повторной доставки) или dead (попытки исчерпаны).
+const (
+	// NotificationEnqueued - планировщик отметил напоминание, публикация ещё не подтверждена
+	NotificationEnqueued = "enqueued"
+	// NotificationPublished - брокер подтвердил публикацию
+	NotificationPublished = "published"
+	// NotificationSending - рассыльщик взял уведомление и доставляет его
+	NotificationSending = "sending"
+	// NotificationFailed - доставка не удалась, сообщение вернётся из очереди повторов
+	NotificationFailed = "failed"
+	// NotificationDead - попытки исчерпаны, сообщение в очереди недоставленных
+	NotificationDead = "dead"
+	NotificationSent = "sent"
+)
+
+// Notification - уведомление о событии, которое планировщик кладёт в очередь рассыльщику.
+type Notification struct {
+	EventID string
+	Title   string
+	At      time.Time // дата события, она же наступление (occurrence) напоминания
+	UserID  string
+	Offset  time.Duration
+	Channel string
+}
+
+// Key однозначно определяет отправку: событие, напоминание и наступление события.
+// Если событие перенесли на другое время, ключ меняется и напоминание будет отправлено заново.
+func (n Notification) Key() string {
+	return fmt.Sprintf("%s|%d|%s|%d", n.EventID, int64(n.Offset), n.Channel, n.At.UnixNano())
+}
+
+// DueAt - момент, когда напоминание должно сработать.
+func (n Notification) DueAt() time.Time {
+	return n.At.Add(-n.Offset)
+}
+
+// WebhookDelivery - результат одной попытки доставки уведомления на webhook получателя.
+type WebhookDelivery struct {
+	Notification Notification
+	URL          string
+	Attempt      int
+	StatusCode   int    // 0, если ответ не получен
+	Error        string // ошибка запроса или пустая строка
+	DeliveredAt  time.Time
+}
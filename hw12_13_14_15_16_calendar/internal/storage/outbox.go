@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы изменений событий, записываемых в outbox.
+const (
+	EventCreated = "event.created"
+	EventUpdated = "event.updated"
+	EventDeleted = "event.deleted"
+)
+
+// OutboxRecord - изменение события, записанное в той же транзакции, что и само изменение.
+// Relay публикует такие записи в очередь и отмечает доставленными.
+type OutboxRecord struct {
+	ID        int64
+	Type      string
+	EventID   string
+	Payload   []byte // JSON события; для удаления - только id
+	CreatedAt time.Time
+}
+
+type outboxEventJSON struct {
+	ID          string               `json:"id"`
+	Title       string               `json:"title,omitempty"`
+	At          string               `json:"at,omitempty"`       // RFC3339 format
+	Duration    string               `json:"duration,omitempty"` // Go duration format
+	Description string               `json:"description,omitempty"`
+	UserID      string               `json:"user_id,omitempty"`
+	Reminders   []outboxReminderJSON `json:"reminders,omitempty"`
+}
+
+type outboxReminderJSON struct {
+	Offset  string `json:"offset"`
+	Channel string `json:"channel"`
+}
+
+// NewOutboxRecord готовит запись outbox для изменения события.
+func NewOutboxRecord(kind string, e Event) (OutboxRecord, error) {
+	v := outboxEventJSON{ID: e.ID}
+	if kind != EventDeleted {
+		v.Title = e.Title
+		v.At = e.At.Format(time.RFC3339Nano)
+		v.Duration = e.Duration.String()
+		v.Description = e.Description
+		v.UserID = e.UserID
+		for _, r := range e.Reminders {
+			v.Reminders = append(v.Reminders, outboxReminderJSON{Offset: r.Offset.String(), Channel: r.Channel})
+		}
+	}
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return OutboxRecord{}, err
+	}
+	return OutboxRecord{Type: kind, EventID: e.ID, Payload: payload, CreatedAt: time.Now()}, nil
+}
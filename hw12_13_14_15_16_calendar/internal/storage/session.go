@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type sessionKey struct{}
+
+type session struct {
+	written atomic.Bool
+}
+
+// WithSession начинает сессию одного запроса к сервису. Хранилище с репликами отмечает
+// в ней записи, и после первой записи чтения той же сессии идут в основную базу:
+// клиент видит собственные изменения, даже если реплика ещё не догнала основную.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+// MarkWritten отмечает запись в сессии контекста. Без сессии ничего не делает.
+func MarkWritten(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.written.Store(true)
+	}
+}
+
+// WithPrimary возвращает контекст, чтения в котором идут в основную базу, даже если
+// в сессии ещё не было записей. Нужен для проверок перед записью: ответ отстающей
+// реплики (например, "не найдено" для только что созданного события) сорвал бы запись.
+func WithPrimary(ctx context.Context) context.Context {
+	s := &session{}
+	s.written.Store(true)
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// Written сообщает, была ли запись в сессии контекста.
+func Written(ctx context.Context) bool {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	return ok && s.written.Load()
+}
@@ -0,0 +1,200 @@
+package sqlstorage
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval - time.Duration в колонке INTERVAL. В базу пишется числом микросекунд,
+// поэтому значение сохраняется без потерь с точностью Postgres (1µs); наносекунды отбрасываются.
+//
+// Читается текст в формате IntervalStyle = postgres: "1 year 2 mons -3 days -04:05:06.789".
+// Другие форматы (iso_8601, sql_standard) отклоняются, поэтому стиль закрепляется
+// в каждом соединении (см. pinIntervalStyle). Месяц считается за 30 дней, год - за 12 месяцев,
+// как при сравнении интервалов в самом Postgres. NULL читается как 0.
+type Interval time.Duration
+
+func (i Interval) Value() (driver.Value, error) {
+	return strconv.FormatInt(time.Duration(i).Microseconds(), 10) + " microseconds", nil
+}
+
+func (i *Interval) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*i = 0
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("sqlstorage: cannot scan %T into Interval", src)
+	}
+	d, err := parseInterval(s)
+	if err != nil {
+		return err
+	}
+	*i = Interval(d)
+	return nil
+}
+
+// intervalStyleOption - параметр соединения, задающий формат, который разбирает Interval.Scan.
+const intervalStyleOption = "-c IntervalStyle=postgres"
+
+var dsnOptionsKey = regexp.MustCompile(`(^|\s)options\s*=`)
+
+// pinIntervalStyle добавляет в DSN options='-c IntervalStyle=postgres': сервер, база или роль
+// могут задать другой IntervalStyle, а разбирается только формат postgres.
+// Поддерживаются оба вида DSN lib/pq; к своим options в виде key=value стиль нужно дописать самому.
+func pinIntervalStyle(dsn string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", fmt.Errorf("sqlstorage: parse dsn: %w", err)
+		}
+		q := u.Query()
+		q.Set("options", strings.TrimSpace(q.Get("options")+" "+intervalStyleOption))
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+	if dsnOptionsKey.MatchString(dsn) {
+		if strings.Contains(dsn, "IntervalStyle=postgres") {
+			return dsn, nil
+		}
+		return "", errors.New("sqlstorage: dsn sets options, add " + intervalStyleOption + " to them")
+	}
+	return strings.TrimSpace(dsn + " options='" + intervalStyleOption + "'"), nil
+}
+
+// pqInterval возвращает значение для nullable колонки: нулевая длительность пишется как NULL.
+func pqInterval(d time.Duration) any {
+	if d == 0 {
+		return nil
+	}
+	return Interval(d)
+}
+
+const (
+	microsPerDay   = int64(24 * time.Hour / time.Microsecond)
+	microsPerMonth = 30 * microsPerDay
+)
+
+var errIntervalRange = errors.New("interval out of time.Duration range")
+
+// parseInterval разбирает интервал в формате IntervalStyle = postgres.
+// Поля "N years", "N mons", "N days" и время "[-]HH:MM:SS[.ffffff]" знаковые независимо друг от друга.
+func parseInterval(s string) (time.Duration, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("sqlstorage: empty interval")
+	}
+
+	var micros int64
+	add := func(v int64) error {
+		if (v > 0 && micros > math.MaxInt64-v) || (v < 0 && micros < math.MinInt64-v) {
+			return errIntervalRange
+		}
+		micros += v
+		return nil
+	}
+
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Contains(f, ":") {
+			t, err := parseClock(f)
+			if err != nil {
+				return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, err)
+			}
+			if err := add(t); err != nil {
+				return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, err)
+			}
+			continue
+		}
+
+		if i+1 >= len(fields) {
+			return 0, fmt.Errorf("sqlstorage: interval %q: missing unit after %q", s, f)
+		}
+		n, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, err)
+		}
+		i++
+
+		var unit int64
+		switch fields[i] {
+		case "year", "years":
+			unit = 12 * microsPerMonth
+		case "mon", "mons":
+			unit = microsPerMonth
+		case "day", "days":
+			unit = microsPerDay
+		default:
+			return 0, fmt.Errorf("sqlstorage: interval %q: unknown unit %q", s, fields[i])
+		}
+		if n != 0 && (n > math.MaxInt64/unit || n < math.MinInt64/unit) {
+			return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, errIntervalRange)
+		}
+		if err := add(n * unit); err != nil {
+			return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, err)
+		}
+	}
+
+	if micros > math.MaxInt64/int64(time.Microsecond) || micros < math.MinInt64/int64(time.Microsecond) {
+		return 0, fmt.Errorf("sqlstorage: interval %q: %w", s, errIntervalRange)
+	}
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
+// parseClock разбирает время интервала "[-+]HH:MM:SS[.ffffff]" в микросекунды.
+// Часы не ограничены 24: Postgres не переносит их в дни.
+func parseClock(s string) (int64, error) {
+	sign := int64(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	h, err := strconv.ParseUint(parts[0], 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours %q", parts[0])
+	}
+	m, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || m > 59 {
+		return 0, fmt.Errorf("invalid minutes %q", parts[1])
+	}
+	sec, frac, _ := strings.Cut(parts[2], ".")
+	sc, err := strconv.ParseUint(sec, 10, 8)
+	if err != nil || sc > 59 {
+		return 0, fmt.Errorf("invalid seconds %q", parts[2])
+	}
+	var us uint64
+	if frac != "" {
+		if len(frac) > 6 {
+			return 0, fmt.Errorf("invalid fraction %q", frac)
+		}
+		if us, err = strconv.ParseUint(frac+strings.Repeat("0", 6-len(frac)), 10, 32); err != nil {
+			return 0, fmt.Errorf("invalid fraction %q", frac)
+		}
+	}
+
+	const microsPerHour = uint64(time.Hour / time.Microsecond)
+	if h > math.MaxInt64/microsPerHour-1 {
+		return 0, errIntervalRange
+	}
+	total := h*microsPerHour + (m*60+sc)*uint64(time.Second/time.Microsecond) + us
+	return sign * int64(total), nil
+}
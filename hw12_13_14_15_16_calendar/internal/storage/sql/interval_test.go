@@ -0,0 +1,133 @@
+package sqlstorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIntervalScan(t *testing.T) {
+	const day = 24 * time.Hour
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"00:00:00", 0},
+		{"01:30:00", 90 * time.Minute},
+		{"00:00:00.5", 500 * time.Millisecond},
+		{"00:00:00.000001", time.Microsecond},
+		{"1 day 02:00:00", day + 2*time.Hour},
+		{"3 days", 3 * day},
+		{"49:00:00", 49 * time.Hour},
+		{"-00:15:00", -15 * time.Minute},
+		{"-1 days -02:00:00", -day - 2*time.Hour},
+		{"1 day -02:00:00", 22 * time.Hour},
+		{"1 mon 2 days", 32 * day},
+		{"1 year -1 mons", 330 * day},
+		{"-2 years", -720 * day},
+	}
+	for _, c := range cases {
+		var got Interval
+		if err := got.Scan([]byte(c.in)); err != nil {
+			t.Errorf("Scan(%q): %v", c.in, err)
+			continue
+		}
+		if time.Duration(got) != c.want {
+			t.Errorf("Scan(%q) = %v, want %v", c.in, time.Duration(got), c.want)
+		}
+	}
+}
+
+func TestIntervalScanInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"1h30m",
+		"5",
+		"1 week",
+		"00:61:00",
+		"00:00:00.1234567",
+		"P1DT2H",
+		"400 years",
+	} {
+		var got Interval
+		if err := got.Scan(in); err == nil {
+			t.Errorf("Scan(%q) = %v, want error", in, time.Duration(got))
+		}
+	}
+}
+
+func TestIntervalScanNull(t *testing.T) {
+	got := Interval(time.Hour)
+	if err := got.Scan(nil); err != nil || got != 0 {
+		t.Errorf("Scan(nil) = %v, %v; want 0", time.Duration(got), err)
+	}
+}
+
+func TestIntervalValue(t *testing.T) {
+	cases := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0 microseconds"},
+		{26 * time.Hour, "93600000000 microseconds"},
+		{-1500 * time.Millisecond, "-1500000 microseconds"},
+		{1500 * time.Nanosecond, "1 microseconds"},
+	}
+	for _, c := range cases {
+		v, err := Interval(c.in).Value()
+		if err != nil || v != c.want {
+			t.Errorf("Value(%v) = %v, %v; want %q", c.in, v, err, c.want)
+		}
+	}
+}
+
+// Формат зависит от IntervalStyle соединения; кроме postgres, ни один не разбирается,
+// поэтому стиль закрепляется в DSN (pinIntervalStyle).
+func TestIntervalScanOtherStyles(t *testing.T) {
+	for style, in := range map[string]string{
+		"iso_8601":          "P1DT2H",
+		"iso_8601 negative": "PT-15M",
+		"sql_standard":      "1 2:00:00",
+		"sql_standard ym":   "1-2",
+		"postgres_verbose":  "@ 1 day 2 hours",
+	} {
+		var got Interval
+		if err := got.Scan(in); err == nil {
+			t.Errorf("%s: Scan(%q) = %v, want error", style, in, time.Duration(got))
+		}
+	}
+}
+
+func TestPinIntervalStyle(t *testing.T) {
+	cases := []struct {
+		dsn  string
+		want string
+	}{
+		{"host=db dbname=calendar", "host=db dbname=calendar options='-c IntervalStyle=postgres'"},
+		{"", "options='-c IntervalStyle=postgres'"},
+		{"host=db options='-c IntervalStyle=postgres -c search_path=cal'",
+			"host=db options='-c IntervalStyle=postgres -c search_path=cal'"},
+		{"postgres://u:p@db:5432/calendar?sslmode=disable",
+			"postgres://u:p@db:5432/calendar?options=-c+IntervalStyle%3Dpostgres&sslmode=disable"},
+		{"postgresql://db/calendar?options=-c%20search_path%3Dcal",
+			"postgresql://db/calendar?options=-c+search_path%3Dcal+-c+IntervalStyle%3Dpostgres"},
+	}
+	for _, c := range cases {
+		got, err := pinIntervalStyle(c.dsn)
+		if err != nil {
+			t.Errorf("pinIntervalStyle(%q): %v", c.dsn, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("pinIntervalStyle(%q) = %q, want %q", c.dsn, got, c.want)
+		}
+		if _, err := pq.NewConnector(got); err != nil {
+			t.Errorf("pinIntervalStyle(%q) = %q: not accepted by lib/pq: %v", c.dsn, got, err)
+		}
+	}
+
+	if _, err := pinIntervalStyle("host=db options='-c search_path=cal'"); err == nil {
+		t.Error("expected error for key=value dsn with options lacking IntervalStyle")
+	}
+}
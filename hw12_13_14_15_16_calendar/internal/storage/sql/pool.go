@@ -0,0 +1,220 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PoolConfig - настройки пула соединений; нулевые значения оставляют умолчания database/sql.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// RetryConfig - повторные попытки. Пауза начинается с Backoff и удваивается до MaxBackoff.
+type RetryConfig struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (r RetryConfig) withDefaults(attempts int, backoff time.Duration) RetryConfig {
+	if r.Attempts <= 0 {
+		r.Attempts = attempts
+	}
+	if r.Backoff <= 0 {
+		r.Backoff = backoff
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 30 * backoff
+	}
+	return r
+}
+
+func (r RetryConfig) delay(attempt int) time.Duration {
+	d := r.Backoff
+	for i := 1; i < attempt && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.MaxBackoff)
+}
+
+// WithPool задаёт настройки пула соединений.
+func WithPool(cfg PoolConfig) Option {
+	return func(s *Storage) {
+		s.pool = cfg
+	}
+}
+
+// WithConnectRetry включает повтор подключения при старте: база в docker-compose
+// или k8s часто поднимается позже сервиса.
+func WithConnectRetry(cfg RetryConfig) Option {
+	return func(s *Storage) {
+		s.connectRetry = cfg.withDefaults(10, time.Second)
+	}
+}
+
+// WithReadRetry задаёт повторы читающих запросов при временных ошибках соединения.
+// Запросы, меняющие данные, не повторяются: после обрыва неизвестно, применились ли они.
+func WithReadRetry(cfg RetryConfig) Option {
+	return func(s *Storage) {
+		s.readRetry = cfg.withDefaults(3, 50*time.Millisecond)
+	}
+}
+
+// WithStatementTimeout ограничивает время одной операции, если у контекста нет более
+// раннего дедлайна. Внутри транзакций то же ограничение передаётся серверу через
+// SET LOCAL statement_timeout, чтобы он прервал запрос сам.
+func WithStatementTimeout(d time.Duration) Option {
+	return func(s *Storage) {
+		s.statementTimeout = d
+	}
+}
+
+func (s *Storage) Connect(ctx context.Context) error {
+	retry := s.connectRetry
+	if retry.Attempts <= 0 {
+		retry.Attempts = 1
+	}
+
+	dsn, err := pinIntervalStyle(s.dsn)
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		var db *sqlx.DB
+		if db, err = sqlx.ConnectContext(ctx, "postgres", dsn); err == nil {
+			s.db = db
+			applyPool(db, s.pool)
+			return s.connectReplicas()
+		}
+		if attempt >= retry.Attempts || ctx.Err() != nil {
+			break
+		}
+
+		delay := retry.delay(attempt)
+		if s.logger != nil {
+			s.logger.ErrorContext(ctx, "failed to connect to db, retrying",
+				"attempt", attempt, "retry_in", delay, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return fmt.Errorf("connect to db: %w", err)
+}
+
+func applyPool(db *sqlx.DB, pool PoolConfig) {
+	if pool.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	}
+}
+
+// withTimeout ограничивает контекст операции statementTimeout.
+func (s *Storage) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.statementTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= s.statementTimeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.statementTimeout)
+}
+
+// beginTx открывает транзакцию и передаёт серверу оставшееся до дедлайна время
+// как statement_timeout.
+func (s *Storage) beginTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		ms := time.Until(deadline).Milliseconds()
+		if ms < 1 {
+			ms = 1
+		}
+		// SET не принимает параметры, значение - число, подставлять безопасно
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", ms)); err != nil {
+			_ = tx.Rollback()
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// read выполняет читающий запрос, повторяя его при временных ошибках соединения.
+func (s *Storage) read(ctx context.Context, fn func(ctx context.Context) error) error {
+	retry := s.readRetry
+	if retry.Attempts <= 0 {
+		retry.Attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= retry.Attempts || !isTransient(err) {
+			return err
+		}
+
+		delay := retry.delay(attempt)
+		if s.logger != nil {
+			s.logger.DebugContext(ctx, "transient db error, retrying read",
+				"attempt", attempt, "retry_in", delay, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Коды ошибок Postgres, после которых запрос стоит повторить.
+var transientCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+	"57P02": true, // crash_shutdown
+	"57P03": true, // cannot_connect_now
+}
+
+// isTransient сообщает, что ошибка вызвана состоянием соединения или сервера,
+// а не самим запросом. Отмена и истечение контекста временными не считаются.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// класс 08 - connection_exception
+		return pqErr.Code.Class() == "08" || transientCodes[pqErr.Code]
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,111 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestIsTransient(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errors.New("syntax error"), false},
+		{context.Canceled, false},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), false},
+		{driver.ErrBadConn, true},
+		{&pq.Error{Code: "08006"}, true}, // connection_failure
+		{&pq.Error{Code: "40001"}, true},
+		{&pq.Error{Code: "23505"}, false}, // unique_violation
+		{fmt.Errorf("wrapped: %w", &pq.Error{Code: "57P01"}), true},
+	}
+	for _, c := range cases {
+		if got := isTransient(c.err); got != c.want {
+			t.Errorf("isTransient(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestReadRetry(t *testing.T) {
+	s := New("", WithReadRetry(RetryConfig{Attempts: 3, Backoff: time.Millisecond}))
+
+	calls := 0
+	err := s.read(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on third attempt, err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = s.read(context.Background(), func(context.Context) error {
+		calls++
+		return &pq.Error{Code: "42P01"} // undefined_table
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("non-transient errors must not be retried, err=%v calls=%d", err, calls)
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	s := New("", WithStatementTimeout(50*time.Millisecond))
+
+	ctx, cancel := s.withTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) > 50*time.Millisecond {
+		t.Fatalf("expected statement timeout deadline, got %v %v", deadline, ok)
+	}
+
+	// более ранний дедлайн вызывающего не продлевается
+	parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelParent()
+	ctx, cancel = s.withTimeout(parent)
+	defer cancel()
+	if d, _ := ctx.Deadline(); time.Until(d) > 10*time.Millisecond {
+		t.Fatalf("expected caller deadline to be kept, got %v", d)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	r := RetryConfig{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := r.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestConnectRetry(t *testing.T) {
+	// порт 1 закрыт - каждая попытка сразу получает отказ в соединении
+	s := New("postgres://calendar@127.0.0.1:1/calendar?sslmode=disable",
+		WithConnectRetry(RetryConfig{Attempts: 3, Backoff: 20 * time.Millisecond}))
+
+	start := time.Now()
+	err := s.Connect(context.Background())
+	if err == nil {
+		t.Fatal("expected connect to fail")
+	}
+	// между тремя попытками две паузы: 20ms и 40ms
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("expected retries with backoff, finished in %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Connect(ctx); err == nil {
+		t.Fatal("expected connect to stop on canceled context")
+	}
+}
@@ -0,0 +1,97 @@
+package sqlstorage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaCooldown - сколько реплика пропускается после ошибки соединения.
+const replicaCooldown = 5 * time.Second
+
+type replica struct {
+	index int
+	dsn   string
+	db    *sqlx.DB
+	// время (UnixNano), до которого реплика считается недоступной
+	downUntil atomic.Int64
+}
+
+func (r *replica) available(now time.Time) bool {
+	return r.db != nil && now.UnixNano() >= r.downUntil.Load()
+}
+
+// WithReplicas задаёт реплики для чтения событий (GetEvent, ListEvents*). Реплики выбираются
+// по кругу; если реплика недоступна, чтение выполняется в основной базе. Чтения после записи
+// в той же сессии (storage.WithSession) тоже идут в основную базу.
+func WithReplicas(dsns ...string) Option {
+	return func(s *Storage) {
+		s.replicas = s.replicas[:0]
+		for i, dsn := range dsns {
+			s.replicas = append(s.replicas, &replica{index: i, dsn: dsn})
+		}
+	}
+}
+
+// connectReplicas открывает пулы реплик. Соединения устанавливаются лениво, поэтому
+// недоступная при старте реплика не мешает запуску: до восстановления её заменяет основная база.
+func (s *Storage) connectReplicas() error {
+	for _, r := range s.replicas {
+		dsn, err := pinIntervalStyle(r.dsn)
+		if err != nil {
+			return err
+		}
+		db, err := sqlx.Open("postgres", dsn)
+		if err != nil {
+			return err
+		}
+		applyPool(db, s.pool)
+		r.db = db
+	}
+	return nil
+}
+
+func (s *Storage) closeReplicas() {
+	for _, r := range s.replicas {
+		if r.db != nil {
+			r.db.Close()
+		}
+	}
+}
+
+// pickReplica выбирает следующую доступную реплику или nil, если читать нужно из основной базы.
+func (s *Storage) pickReplica(ctx context.Context) *replica {
+	if len(s.replicas) == 0 || storage.Written(ctx) {
+		return nil
+	}
+	now := time.Now()
+	start := s.nextReplica.Add(1)
+	for i := range uint64(len(s.replicas)) {
+		if r := s.replicas[(start+i)%uint64(len(s.replicas))]; r.available(now) {
+			return r
+		}
+	}
+	return nil
+}
+
+// readEvents выполняет чтение событий на реплике, а при её недоступности - в основной базе
+// с обычными повторами.
+func (s *Storage) readEvents(ctx context.Context, fn func(ctx context.Context, db *sqlx.DB) error) error {
+	if r := s.pickReplica(ctx); r != nil {
+		err := fn(ctx, r.db)
+		if err == nil || !isTransient(err) {
+			return err
+		}
+		r.downUntil.Store(time.Now().Add(replicaCooldown).UnixNano())
+		if s.logger != nil {
+			s.logger.ErrorContext(ctx, "replica read failed, falling back to primary",
+				"replica", r.index, "err", err)
+		}
+	}
+	return s.read(ctx, func(ctx context.Context) error {
+		return fn(ctx, s.db)
+	})
+}
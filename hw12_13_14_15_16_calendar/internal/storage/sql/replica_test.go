@@ -0,0 +1,111 @@
+package sqlstorage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/jmoiron/sqlx"
+)
+
+func newWithReplicas(n int) *Storage {
+	dsns := make([]string, n)
+	s := New("primary", WithReplicas(dsns...))
+	s.db = &sqlx.DB{}
+	for _, r := range s.replicas {
+		r.db = &sqlx.DB{}
+	}
+	return s
+}
+
+func TestPickReplicaRoundRobin(t *testing.T) {
+	s := newWithReplicas(3)
+	ctx := context.Background()
+
+	seen := map[int]int{}
+	for range 6 {
+		seen[s.pickReplica(ctx).index]++
+	}
+	for i := range 3 {
+		if seen[i] != 2 {
+			t.Fatalf("expected even distribution, got %v", seen)
+		}
+	}
+
+	// недоступная реплика пропускается
+	s.replicas[1].downUntil.Store(time.Now().Add(time.Minute).UnixNano())
+	for range 6 {
+		if s.pickReplica(ctx).index == 1 {
+			t.Fatal("unavailable replica picked")
+		}
+	}
+
+	for _, r := range s.replicas {
+		r.downUntil.Store(time.Now().Add(time.Minute).UnixNano())
+	}
+	if r := s.pickReplica(ctx); r != nil {
+		t.Fatalf("expected primary when all replicas are down, got replica %d", r.index)
+	}
+}
+
+func TestPickReplicaReadYourWrites(t *testing.T) {
+	s := newWithReplicas(2)
+
+	ctx := storage.WithSession(context.Background())
+	if s.pickReplica(ctx) == nil {
+		t.Fatal("expected replica before any write")
+	}
+	storage.MarkWritten(ctx)
+	if s.pickReplica(ctx) != nil {
+		t.Fatal("expected primary after a write in the same session")
+	}
+	if s.pickReplica(storage.WithSession(context.Background())) == nil {
+		t.Fatal("expected replica for another session")
+	}
+
+	if s.pickReplica(storage.WithPrimary(context.Background())) != nil {
+		t.Fatal("expected primary for a primary-only read")
+	}
+
+	if New("primary").pickReplica(context.Background()) != nil {
+		t.Fatal("expected primary without replicas")
+	}
+}
+
+func TestReadEventsFallback(t *testing.T) {
+	s := newWithReplicas(1)
+	replicaDB := s.replicas[0].db
+
+	var calls []*sqlx.DB
+	err := s.readEvents(context.Background(), func(_ context.Context, db *sqlx.DB) error {
+		calls = append(calls, db)
+		if db == replicaDB {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 || calls[0] != replicaDB || calls[1] != s.db {
+		t.Fatalf("expected replica then primary, got %d calls", len(calls))
+	}
+	if s.replicas[0].available(time.Now()) {
+		t.Fatal("failed replica should be put on cooldown")
+	}
+
+	// ошибка запроса, а не соединения, возвращается без обращения к основной базе
+	s = newWithReplicas(1)
+	wantErr := errors.New("syntax error")
+	calls = calls[:0]
+	err = s.readEvents(context.Background(), func(_ context.Context, db *sqlx.DB) error {
+		calls = append(calls, db)
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) || len(calls) != 1 {
+		t.Fatalf("expected single failed replica call, got %v after %d calls", err, len(calls))
+	}
+}
@@ -4,59 +4,117 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/migrations"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type Storage struct {
-	db  *sqlx.DB
-	dsn string
+	db     *sqlx.DB
+	dsn    string
+	logger Logger
+
+	pool             PoolConfig
+	connectRetry     RetryConfig
+	readRetry        RetryConfig
+	statementTimeout time.Duration
+
+	replicas    []*replica
+	nextReplica atomic.Uint64
 }
 
-func New(dsn string) *Storage {
-	return &Storage{dsn: dsn}
+type Option func(*Storage)
+
+// WithLogger включает запись операций хранилища в лог.
+func WithLogger(l Logger) Option {
+	return func(s *Storage) {
+		s.logger = l
+	}
 }
 
-func (s *Storage) Connect(ctx context.Context) error {
-	db, err := sqlx.ConnectContext(ctx, "postgres", s.dsn)
-	if err != nil {
-		return err
+func New(dsn string, opts ...Option) *Storage {
+	s := &Storage{dsn: dsn}
+	for _, opt := range opts {
+		opt(s)
 	}
-	s.db = db
-	return nil
+	return s
+}
+
+// Ping проверяет соединение с базой, используется в проверке готовности.
+func (s *Storage) Ping(ctx context.Context) error {
+	if s.db == nil {
+		return errors.New("sqlstorage: not connected")
+	}
+	return s.db.PingContext(ctx)
+}
+
+// Migrate применяет встроенные миграции, которых ещё нет в базе.
+func (s *Storage) Migrate(ctx context.Context) error {
+	if s.db == nil {
+		return errors.New("sqlstorage: not connected")
+	}
+	return migrations.Up(ctx, s.db.DB)
 }
 
 func (s *Storage) Close(_ context.Context) error {
 	if s.db == nil {
 		return nil
 	}
+	s.closeReplicas()
 	return s.db.Close()
 }
 
-func (s *Storage) CreateEvent(ctx context.Context, e storage.Event) error {
+func (s *Storage) CreateEvent(ctx context.Context, e storage.Event) (err error) {
+	ctx, done := s.begin(ctx, "CreateEvent")
+	defer func() { done(err) }()
+	storage.MarkWritten(ctx)
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
 	query := `
 		INSERT INTO events (id, title, at, duration, description, user_id, notify_before)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := s.db.ExecContext(ctx, query, e.ID, e.Title, e.At, pqInterval(e.Duration),
-		e.Description, e.UserID, pqInterval(e.NotifyBefore))
+	_, err = tx.ExecContext(ctx, query, e.ID, e.Title, e.At, pqInterval(e.Duration),
+		e.Description, e.UserID, pqInterval(e.NotifyBefore()))
 	if err != nil {
 		return err
 	}
-	return nil
+	if err := insertReminders(ctx, tx, e.ID, e.Reminders); err != nil {
+		return err
+	}
+	if err := writeOutbox(ctx, tx, storage.EventCreated, e); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
+func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) (err error) {
+	ctx, done := s.begin(ctx, "UpdateEvent")
+	defer func() { done(err) }()
+	storage.MarkWritten(ctx)
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
 	query := `
 		UPDATE events
 		SET title = $2, at = $3, duration = $4, description = $5, user_id = $6, notify_before = $7
 		WHERE id = $1
 	`
-	res, err := s.db.ExecContext(ctx, query, e.ID, e.Title, e.At,
-		pqInterval(e.Duration), e.Description, e.UserID, pqInterval(e.NotifyBefore))
+	res, err := tx.ExecContext(ctx, query, e.ID, e.Title, e.At,
+		pqInterval(e.Duration), e.Description, e.UserID, pqInterval(e.NotifyBefore()))
 	if err != nil {
 		return err
 	}
@@ -64,32 +122,132 @@ func (s *Storage) UpdateEvent(ctx context.Context, e storage.Event) error {
 	if n == 0 {
 		return storage.ErrNotFound
 	}
-	return nil
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_reminders WHERE event_id = $1`, e.ID); err != nil {
+		return err
+	}
+	if err := insertReminders(ctx, tx, e.ID, e.Reminders); err != nil {
+		return err
+	}
+	if err := writeOutbox(ctx, tx, storage.EventUpdated, e); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (s *Storage) DeleteEvent(ctx context.Context, id string) error {
-	res, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id)
+func (s *Storage) DeleteEvent(ctx context.Context, id string) (err error) {
+	ctx, done := s.begin(ctx, "DeleteEvent")
+	defer func() { done(err) }()
+	storage.MarkWritten(ctx)
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// напоминания удаляются каскадно
+	res, err := tx.ExecContext(ctx, `DELETE FROM events WHERE id = $1`, id)
 	if err != nil {
 		return err
 	}
 	if n, _ := res.RowsAffected(); n == 0 {
 		return storage.ErrNotFound
 	}
+	if err := writeOutbox(ctx, tx, storage.EventDeleted, storage.Event{ID: id}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeOutbox записывает изменение события в outbox в транзакции самого изменения.
+func writeOutbox(ctx context.Context, tx *sqlx.Tx, kind string, e storage.Event) error {
+	rec, err := storage.NewOutboxRecord(kind, e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO outbox (type, event_id, payload) VALUES ($1, $2, $3)`,
+		rec.Type, rec.EventID, rec.Payload)
+	return err
+}
+
+func insertReminders(ctx context.Context, tx *sqlx.Tx, eventID string, reminders []storage.Reminder) error {
+	for i, r := range reminders {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO event_reminders (event_id, position, offset_before, channel)
+			VALUES ($1, $2, $3, $4)`, eventID, i, Interval(r.Offset), r.Channel)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error) {
+// loadReminders дозагружает напоминания для уже выбранных событий одним запросом.
+func loadReminders(ctx context.Context, db *sqlx.DB, events []storage.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(events))
+	idx := make(map[string]int, len(events))
+	for i, e := range events {
+		ids = append(ids, e.ID)
+		idx[e.ID] = i
+	}
+
+	rows, err := db.QueryxContext(ctx, `
+		SELECT event_id, offset_before, channel
+		FROM event_reminders
+		WHERE event_id = ANY($1)
+		ORDER BY event_id, position`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r struct {
+			EventID string   `db:"event_id"`
+			Offset  Interval `db:"offset_before"`
+			Channel string   `db:"channel"`
+		}
+		if err := rows.StructScan(&r); err != nil {
+			return err
+		}
+		i, ok := idx[r.EventID]
+		if !ok {
+			continue
+		}
+		events[i].Reminders = append(events[i].Reminders, storage.Reminder{
+			Offset:  time.Duration(r.Offset),
+			Channel: r.Channel,
+		})
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id string) (ev storage.Event, err error) {
+	ctx, done := s.begin(ctx, "GetEvent")
+	defer func() { done(err) }()
+
+	err = s.readEvents(ctx, func(ctx context.Context, db *sqlx.DB) error {
+		ev, err = getEvent(ctx, db, id)
+		return err
+	})
+	return ev, err
+}
+
+func getEvent(ctx context.Context, db *sqlx.DB, id string) (storage.Event, error) {
 	var e struct {
-		ID           string         `db:"id"`
-		Title        string         `db:"title"`
-		At           time.Time      `db:"at"`
-		Duration     sql.NullString `db:"duration"`
-		Description  sql.NullString `db:"description"`
-		UserID       sql.NullString `db:"user_id"`
-		NotifyBefore sql.NullString `db:"notify_before"`
-	}
-	err := s.db.GetContext(ctx, &e, `
-		SELECT id, title, at, duration::text as duration, description, user_id, notify_before::text as notify_before 
+		ID          string         `db:"id"`
+		Title       string         `db:"title"`
+		At          time.Time      `db:"at"`
+		Duration    Interval       `db:"duration"`
+		Description sql.NullString `db:"description"`
+		UserID      sql.NullString `db:"user_id"`
+	}
+	err := db.GetContext(ctx, &e, `
+		SELECT id, title, at, duration, description, user_id 
 		FROM events 
 		WHERE id = $1`, id)
 	if err != nil {
@@ -102,27 +260,15 @@ func (s *Storage) GetEvent(ctx context.Context, id string) (storage.Event, error
 		ID:          e.ID,
 		Title:       e.Title,
 		At:          e.At,
+		Duration:    time.Duration(e.Duration),
 		Description: nullStringToString(e.Description),
 		UserID:      nullStringToString(e.UserID),
 	}
-	if e.Duration.Valid {
-		if d, err := time.ParseDuration(sqlIntervalToDurationString(e.Duration.String)); err == nil {
-			ev.Duration = d
-		}
-	}
-	if e.NotifyBefore.Valid {
-		if d, err := time.ParseDuration(sqlIntervalToDurationString(e.NotifyBefore.String)); err == nil {
-			ev.NotifyBefore = d
-		}
-	}
-	return ev, nil
-}
-
-func pqInterval(d time.Duration) interface{} {
-	if d == 0 {
-		return nil
+	events := []storage.Event{ev}
+	if err := loadReminders(ctx, db, events); err != nil {
+		return storage.Event{}, err
 	}
-	return d.String()
+	return events[0], nil
 }
 
 func nullStringToString(ns sql.NullString) string {
@@ -132,110 +278,392 @@ func nullStringToString(ns sql.NullString) string {
 	return ns.String
 }
 
-func sqlIntervalToDurationString(s string) string {
-	// if it is of form HH:MM:SS
-	if len(s) >= 5 && s[2] == ':' {
-		// parse HH:MM:SS -> convert to hours + minutes + seconds
-		var hh, mm, ss int
-		_, err := fmt.Sscanf(s, "%d:%d:%d", &hh, &mm, &ss)
-		if err == nil {
-			return fmt.Sprintf("%dh%dm%ds", hh, mm, ss)
-		}
-	}
-	return s
-}
-
-func (s *Storage) rowsToEvents(rows *sqlx.Rows) ([]storage.Event, error) {
+func rowsToEvents(rows *sqlx.Rows) ([]storage.Event, error) {
 	out := make([]storage.Event, 0)
 
 	for rows.Next() {
 		var e struct {
-			ID           string         `db:"id"`
-			Title        string         `db:"title"`
-			At           time.Time      `db:"at"`
-			Duration     sql.NullString `db:"duration"`
-			Description  sql.NullString `db:"description"`
-			UserID       sql.NullString `db:"user_id"`
-			NotifyBefore sql.NullString `db:"notify_before"`
+			ID          string         `db:"id"`
+			Title       string         `db:"title"`
+			At          time.Time      `db:"at"`
+			Duration    Interval       `db:"duration"`
+			Description sql.NullString `db:"description"`
+			UserID      sql.NullString `db:"user_id"`
 		}
 
 		if err := rows.StructScan(&e); err != nil {
 			return nil, err
 		}
 
-		ev := storage.Event{
+		out = append(out, storage.Event{
 			ID:          e.ID,
 			Title:       e.Title,
 			At:          e.At,
+			Duration:    time.Duration(e.Duration),
 			Description: nullStringToString(e.Description),
 			UserID:      nullStringToString(e.UserID),
-		}
-
-		if e.Duration.Valid {
-			if d, err := time.ParseDuration(sqlIntervalToDurationString(e.Duration.String)); err == nil {
-				ev.Duration = d
-			}
-		}
-		if e.NotifyBefore.Valid {
-			if d, err := time.ParseDuration(sqlIntervalToDurationString(e.NotifyBefore.String)); err == nil {
-				ev.NotifyBefore = d
-			}
-		}
-
-		out = append(out, ev)
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return out, nil
 }
 
-func (s *Storage) ListEvents(ctx context.Context) ([]storage.Event, error) {
-	rows, err := s.db.QueryxContext(ctx, `
-		SELECT id, title, at, duration::text as duration, description, user_id, notify_before::text as notify_before
-		FROM events 
-		ORDER BY at`)
+// queryEvents выполняет выборку событий и дозагружает их напоминания.
+func (s *Storage) queryEvents(ctx context.Context, query string, args ...interface{}) (events []storage.Event, err error) {
+	err = s.readEvents(ctx, func(ctx context.Context, db *sqlx.DB) error {
+		events, err = queryEventsOnce(ctx, db, query, args...)
+		return err
+	})
+	return events, err
+}
+
+func queryEventsOnce(ctx context.Context, db *sqlx.DB, query string, args ...interface{}) ([]storage.Event, error) {
+	rows, err := db.QueryxContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	return s.rowsToEvents(rows)
+	events, err := rowsToEvents(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := loadReminders(ctx, db, events); err != nil {
+		return nil, err
+	}
+	return events, nil
 }
 
-func (s *Storage) ListEventsDay(ctx context.Context, dayStart time.Time) ([]storage.Event, error) {
-	rows, err := s.db.QueryxContext(ctx, `
-		SELECT id, title, at, duration::text as duration, description, user_id, notify_before::text as notify_before
+func (s *Storage) ListEvents(ctx context.Context) (_ []storage.Event, err error) {
+	ctx, done := s.begin(ctx, "ListEvents")
+	defer func() { done(err) }()
+
+	return s.queryEvents(ctx, `
+		SELECT id, title, at, duration, description, user_id
+		FROM events 
+		ORDER BY at`)
+}
+
+func (s *Storage) ListEventsDay(ctx context.Context, dayStart time.Time) (_ []storage.Event, err error) {
+	ctx, done := s.begin(ctx, "ListEventsDay")
+	defer func() { done(err) }()
+
+	return s.queryEvents(ctx, `
+		SELECT id, title, at, duration, description, user_id
 		FROM events 
 		WHERE at >= $1 AND at < $2
 		ORDER BY at`, dayStart, dayStart.Add(24*time.Hour))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	return s.rowsToEvents(rows)
 }
 
-func (s *Storage) ListEventsWeek(ctx context.Context, weekStart time.Time) ([]storage.Event, error) {
-	rows, err := s.db.QueryxContext(ctx, `
-		SELECT id, title, at, duration::text as duration, description, user_id, notify_before::text as notify_before
+func (s *Storage) ListEventsWeek(ctx context.Context, weekStart time.Time) (_ []storage.Event, err error) {
+	ctx, done := s.begin(ctx, "ListEventsWeek")
+	defer func() { done(err) }()
+
+	return s.queryEvents(ctx, `
+		SELECT id, title, at, duration, description, user_id
 		FROM events 
 		WHERE at >= $1 AND at < $2
 		ORDER BY at`, weekStart, weekStart.Add(7*24*time.Hour))
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	return s.rowsToEvents(rows)
 }
 
-func (s *Storage) ListEventsMonth(ctx context.Context, monthStart time.Time) ([]storage.Event, error) {
+func (s *Storage) ListEventsMonth(ctx context.Context, monthStart time.Time) (_ []storage.Event, err error) {
+	ctx, done := s.begin(ctx, "ListEventsMonth")
+	defer func() { done(err) }()
+
 	end := time.Date(monthStart.Year(), monthStart.Month(), 1, 0, 0, 0, 0, monthStart.Location()).AddDate(0, 1, 0)
-	rows, err := s.db.QueryxContext(ctx, `
-		SELECT id, title, at, duration::text as duration, description, user_id, notify_before::text as notify_before
+	return s.queryEvents(ctx, `
+		SELECT id, title, at, duration, description, user_id
 		FROM events
 		WHERE at >= $1 AND at < $2
 		ORDER BY at`, monthStart, end)
+}
+
+// ClaimNotification помечает уведомление поставленным в очередь.
+// Возвращает false, если оно уже было поставлено ранее (в том числе до перезапуска планировщика).
+// Перехватывается заново только отметка старше lease, публикация которой так и не была
+// подтверждена (MarkNotificationPublished): планировщик мог упасть между отметкой и публикацией.
+func (s *Storage) ClaimNotification(ctx context.Context, n storage.Notification, lease time.Duration) (_ bool, err error) {
+	ctx, done := s.begin(ctx, "ClaimNotification")
+	defer func() { done(err) }()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO notifications (event_id, reminder_offset, channel, occurrence, status, enqueued_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (event_id, reminder_offset, channel, occurrence)
+		DO UPDATE SET enqueued_at = now()
+		WHERE notifications.status = $5 AND notifications.enqueued_at < now() - $6::interval`,
+		n.EventID, Interval(n.Offset), n.Channel, n.At, storage.NotificationEnqueued, Interval(lease))
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// ReleaseNotification снимает отметку, если поставить уведомление в очередь не удалось.
+func (s *Storage) ReleaseNotification(ctx context.Context, n storage.Notification) (err error) {
+	ctx, done := s.begin(ctx, "ReleaseNotification")
+	defer func() { done(err) }()
+
+	_, err = s.db.ExecContext(ctx, `
+		DELETE FROM notifications
+		WHERE event_id = $1 AND reminder_offset = $2 AND channel = $3 AND occurrence = $4 AND status = $5`,
+		n.EventID, Interval(n.Offset), n.Channel, n.At, storage.NotificationEnqueued)
+	return err
+}
+
+// MarkNotificationPublished отмечает, что брокер принял уведомление: такое напоминание
+// планировщик больше не перехватывает. Если рассыльщик успел взять его раньше, статус не меняется.
+func (s *Storage) MarkNotificationPublished(ctx context.Context, n storage.Notification) (err error) {
+	ctx, done := s.begin(ctx, "MarkNotificationPublished")
+	defer func() { done(err) }()
+
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE notifications SET status = $5
+		WHERE event_id = $1 AND reminder_offset = $2 AND channel = $3 AND occurrence = $4 AND status = $6`,
+		n.EventID, Interval(n.Offset), n.Channel, n.At, storage.NotificationPublished, storage.NotificationEnqueued)
+	return err
+}
+
+// ClaimDelivery берёт уведомление на доставку (status = sending). Возвращает false, если оно
+// уже отправлено, его доставляет другой рассыльщик (и с тех пор не прошло lease) или событие удалено.
+// Строка блокируется на время вставки, поэтому из нескольких одновременных вызовов успешен один.
+func (s *Storage) ClaimDelivery(ctx context.Context, n storage.Notification, lease time.Duration) (_ bool, err error) {
+	ctx, done := s.begin(ctx, "ClaimDelivery")
+	defer func() { done(err) }()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO notifications (event_id, reminder_offset, channel, occurrence, status, sending_at)
+		SELECT $1::uuid, $2::interval, $3::text, $4::timestamptz, $5::text, now()
+		WHERE EXISTS (SELECT 1 FROM events WHERE id = $1)
+		ON CONFLICT (event_id, reminder_offset, channel, occurrence)
+		DO UPDATE SET status = EXCLUDED.status, sending_at = EXCLUDED.sending_at
+		WHERE notifications.status <> $6
+			AND (notifications.status <> $5 OR notifications.sending_at < now() - $7::interval)`,
+		n.EventID, Interval(n.Offset), n.Channel, n.At,
+		storage.NotificationSending, storage.NotificationSent, Interval(lease))
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// MarkNotificationSent завершает доставку, взятую ClaimDelivery.
+func (s *Storage) MarkNotificationSent(ctx context.Context, n storage.Notification) (err error) {
+	ctx, done := s.begin(ctx, "MarkNotificationSent")
+	defer func() { done(err) }()
+	return s.finishDelivery(ctx, n, storage.NotificationSent)
+}
+
+// MarkNotificationFailed отмечает неудачную доставку; dead - попытки исчерпаны
+// и сообщение уходит в очередь недоставленных.
+func (s *Storage) MarkNotificationFailed(ctx context.Context, n storage.Notification, dead bool) (err error) {
+	ctx, done := s.begin(ctx, "MarkNotificationFailed")
+	defer func() { done(err) }()
+
+	status := storage.NotificationFailed
+	if dead {
+		status = storage.NotificationDead
+	}
+	return s.finishDelivery(ctx, n, status)
+}
+
+func (s *Storage) finishDelivery(ctx context.Context, n storage.Notification, status string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET status = $5, sent_at = CASE WHEN $5::text = $7::text THEN now() ELSE sent_at END
+		WHERE event_id = $1 AND reminder_offset = $2 AND channel = $3 AND occurrence = $4 AND status = $6`,
+		n.EventID, Interval(n.Offset), n.Channel, n.At, status, storage.NotificationSending, storage.NotificationSent)
+	return err
+}
+
+func (s *Storage) IsNotificationSent(ctx context.Context, n storage.Notification) (_ bool, err error) {
+	ctx, done := s.begin(ctx, "IsNotificationSent")
+	defer func() { done(err) }()
+
+	var sent bool
+	err = s.read(ctx, func(ctx context.Context) error {
+		return s.db.GetContext(ctx, &sent, `
+			SELECT EXISTS (
+				SELECT 1 FROM notifications
+				WHERE event_id = $1 AND reminder_offset = $2 AND channel = $3 AND occurrence = $4 AND status = $5
+			)`,
+			n.EventID, Interval(n.Offset), n.Channel, n.At, storage.NotificationSent)
+	})
+	return sent, err
+}
+
+func (s *Storage) RecordWebhookDelivery(ctx context.Context, d storage.WebhookDelivery) (err error) {
+	ctx, done := s.begin(ctx, "RecordWebhookDelivery")
+	defer func() { done(err) }()
+
+	n := d.Notification
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (event_id, reminder_offset, occurrence, url, attempt, status_code, error, delivered_at)
+		SELECT $1::uuid, $2::interval, $3::timestamptz, $4::text, $5::int, $6::int, $7::text, $8::timestamptz
+		WHERE EXISTS (SELECT 1 FROM events WHERE id = $1)`,
+		n.EventID, Interval(n.Offset), n.At, d.URL, d.Attempt, d.StatusCode, d.Error, d.DeliveredAt)
+	return err
+}
+
+func (s *Storage) WebhookDeliveries(ctx context.Context, eventID string) (res []storage.WebhookDelivery, err error) {
+	ctx, done := s.begin(ctx, "WebhookDeliveries")
+	defer func() { done(err) }()
+
+	err = s.read(ctx, func(ctx context.Context) error {
+		res, err = s.webhookDeliveries(ctx, eventID)
+		return err
+	})
+	return res, err
+}
+
+func (s *Storage) webhookDeliveries(ctx context.Context, eventID string) ([]storage.WebhookDelivery, error) {
+	rows, err := s.db.QueryxContext(ctx, `
+		SELECT d.event_id, e.title, e.user_id, d.reminder_offset, d.occurrence,
+		       d.url, d.attempt, d.status_code, d.error, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN events e ON e.id = d.event_id
+		WHERE d.event_id = $1
+		ORDER BY d.id`, eventID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	return s.rowsToEvents(rows)
+
+	var res []storage.WebhookDelivery
+	for rows.Next() {
+		var r struct {
+			EventID     string         `db:"event_id"`
+			Title       string         `db:"title"`
+			UserID      sql.NullString `db:"user_id"`
+			Offset      Interval       `db:"reminder_offset"`
+			Occurrence  time.Time      `db:"occurrence"`
+			URL         string         `db:"url"`
+			Attempt     int            `db:"attempt"`
+			StatusCode  int            `db:"status_code"`
+			Error       string         `db:"error"`
+			DeliveredAt time.Time      `db:"delivered_at"`
+		}
+		if err := rows.StructScan(&r); err != nil {
+			return nil, err
+		}
+		d := storage.WebhookDelivery{
+			Notification: storage.Notification{
+				EventID: r.EventID,
+				Title:   r.Title,
+				At:      r.Occurrence,
+				UserID:  nullStringToString(r.UserID),
+				Offset:  time.Duration(r.Offset),
+				Channel: storage.ChannelWebhook,
+			},
+			URL:         r.URL,
+			Attempt:     r.Attempt,
+			StatusCode:  r.StatusCode,
+			Error:       r.Error,
+			DeliveredAt: r.DeliveredAt,
+		}
+		res = append(res, d)
+	}
+	return res, rows.Err()
+}
+
+// ProcessOutbox передаёт fn пачку недоставленных записей outbox в порядке их появления
+// и отмечает доставленными записи с ID, которые вернула fn. Пока fn работает, записи
+// заблокированы (FOR UPDATE SKIP LOCKED) и другие экземпляры relay берут следующие.
+// Изменения события, более ранние записи которого сейчас у другого relay, откладываются,
+// чтобы изменения одного события публиковались по порядку. Транзакция, а с ней и fn,
+// ограничены statement_timeout хранилища.
+func (s *Storage) ProcessOutbox(ctx context.Context, limit int,
+	fn func(ctx context.Context, records []storage.OutboxRecord) []int64,
+) (_ int, err error) {
+	ctx, done := s.begin(ctx, "ProcessOutbox")
+	defer func() { done(err) }()
+
+	tx, err := s.beginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var rows []struct {
+		ID        int64     `db:"id"`
+		Type      string    `db:"type"`
+		EventID   string    `db:"event_id"`
+		Payload   []byte    `db:"payload"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+	err = tx.SelectContext(ctx, &rows, `
+		SELECT id, type, event_id, payload, created_at
+		FROM outbox
+		WHERE delivered_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	// пропущенные более ранние записи заняты другим relay (или ещё не закоммичены)
+	var busy []string
+	err = tx.SelectContext(ctx, &busy, `
+		SELECT DISTINCT event_id
+		FROM outbox
+		WHERE delivered_at IS NULL AND id < $1 AND id <> ALL($2)`,
+		ids[len(ids)-1], pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	skip := make(map[string]struct{}, len(busy))
+	for _, id := range busy {
+		skip[id] = struct{}{}
+	}
+
+	records := make([]storage.OutboxRecord, 0, len(rows))
+	for _, r := range rows {
+		if _, ok := skip[r.EventID]; !ok {
+			records = append(records, storage.OutboxRecord(r))
+		}
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	delivered := fn(ctx, records)
+	if len(delivered) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE outbox SET delivered_at = now() WHERE id = ANY($1) AND delivered_at IS NULL`,
+			pq.Array(delivered))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(delivered), nil
+}
+
+// PurgeOutbox удаляет записи outbox, доставленные раньше before, и возвращает их количество.
+func (s *Storage) PurgeOutbox(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, done := s.begin(ctx, "PurgeOutbox")
+	defer func() { done(err) }()
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM outbox WHERE delivered_at IS NOT NULL AND delivered_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
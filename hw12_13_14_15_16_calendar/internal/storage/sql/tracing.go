@@ -0,0 +1,46 @@
+package sqlstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/storage"
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logger - необязательный логгер операций хранилища. Записи получают поля
+// запроса (request_id, trace_id) из контекста.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+}
+
+// begin открывает клиентский спан операции с базой и ограничивает её время statementTimeout.
+// Возвращаемая функция закрывает спан и пишет операцию в лог; её нужно вызвать с ошибкой операции.
+func (s *Storage) begin(ctx context.Context, op string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, cancel := s.withTimeout(ctx)
+	ctx, span := tracing.Start(ctx, "sqlstorage."+op, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", op),
+		))
+
+	return ctx, func(err error) {
+		cancel()
+		tracing.End(span, err)
+		if s.logger == nil {
+			return
+		}
+		d := time.Since(start)
+		// ожидаемые ошибки предметной области - не сбой хранилища
+		if err != nil && !errors.Is(err, storage.ErrNotFound) && !errors.Is(err, storage.ErrDateBusy) {
+			s.logger.ErrorContext(ctx, "sql operation failed", "op", op, "duration", d, "err", err)
+			return
+		}
+		s.logger.DebugContext(ctx, "sql operation", "op", op, "duration", d)
+	}
+}
@@ -0,0 +1,152 @@
+// Package tlsconfig - TLS для HTTP и gRPC серверов с перечитыванием сертификатов с диска
+// без перезапуска: новые соединения сразу получают обновлённый сертификат и список CA.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/config"
+)
+
+type Logger interface {
+	Info(msg string)
+	Error(msg string)
+}
+
+// Config - файлы сертификата сервера и CA для проверки клиентских сертификатов (mTLS).
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// CAFile - PEM с сертификатами CA, которыми подписаны клиентские сертификаты
+	CAFile string
+}
+
+// Reloader хранит текущие сертификат и CA и перечитывает их при изменении файлов.
+type Reloader struct {
+	cfg    Config
+	logger Logger
+
+	cert atomic.Pointer[tls.Certificate]
+	pool atomic.Pointer[x509.CertPool]
+
+	mu sync.Mutex
+}
+
+// New загружает сертификат и CA. Ошибка чтения при старте фатальна,
+// при перезагрузке - остаются прежние сертификаты.
+func New(logger Logger, cfg Config) (*Reloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("tls: cert_file and key_file are required")
+	}
+	r := &Reloader{cfg: cfg, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload перечитывает файлы. При ошибке текущие сертификаты не меняются.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load key pair: %w", err)
+	}
+	var pool *x509.CertPool
+	if r.cfg.CAFile != "" {
+		pem, err := os.ReadFile(r.cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read ca: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("tls: no certificates in %s", r.cfg.CAFile)
+		}
+	}
+
+	r.cert.Store(&cert)
+	if pool != nil {
+		r.pool.Store(pool)
+	}
+	return nil
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.CAFile != "" {
+		files = append(files, r.cfg.CAFile)
+	}
+	return files
+}
+
+// ServerConfig возвращает конфигурацию TLS сервера. clientAuth - требовать клиентский
+// сертификат, подписанный CA из CAFile.
+func (r *Reloader) ServerConfig(clientAuth bool) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.cert.Load(), nil
+		},
+	}
+	if clientAuth {
+		if r.cfg.CAFile == "" {
+			return nil, errors.New("tls: client authentication requires ca_file")
+		}
+		// цепочка проверяется вручную, чтобы использовать актуальный после перезагрузки CA
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyPeerCertificate = r.verifyClient
+	}
+	return cfg, nil
+}
+
+func (r *Reloader) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("tls: client certificate required")
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		c, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("tls: parse client certificate: %w", err)
+		}
+		certs = append(certs, c)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         r.pool.Load(),
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("tls: verify client certificate: %w", err)
+	}
+	return nil
+}
+
+// Watch перечитывает сертификаты по SIGHUP, а если interval > 0 - ещё и при изменении
+// файлов (время изменения или размер); неудавшаяся перезагрузка повторяется на следующем тике.
+// Работает до отмены контекста.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	config.Watch(ctx, interval, r.files(), nil, func(bool) error {
+		if err := r.Reload(); err != nil {
+			// повторяется на следующем тике: ротация могла записать сертификат раньше ключа
+			r.logger.Error("failed to reload certificates: " + err.Error())
+			return err
+		}
+		r.logger.Info("tls certificates reloaded")
+		return nil
+	})
+}
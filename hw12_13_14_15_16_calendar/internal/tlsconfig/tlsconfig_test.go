@@ -0,0 +1,301 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AnastasiaDAmber/golang_homework/hw12_13_14_15_calendar/internal/logger"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+// issue выпускает сертификат, подписанный parent (или самоподписанный, если parent nil).
+func issue(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) write(t *testing.T, certPath, keyPath string) {
+	t.Helper()
+	writePEM(t, certPath, "CERTIFICATE", c.der)
+	if keyPath != "" {
+		der, err := x509.MarshalECPrivateKey(c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writePEM(t, keyPath, "EC PRIVATE KEY", der)
+	}
+}
+
+func (c *testCert) tls() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type files struct {
+	cert, key, ca string
+}
+
+func newFiles(t *testing.T) files {
+	dir := t.TempDir()
+	return files{
+		cert: filepath.Join(dir, "server.crt"),
+		key:  filepath.Join(dir, "server.key"),
+		ca:   filepath.Join(dir, "ca.crt"),
+	}
+}
+
+// handshake устанавливает TLS соединение с сервером и возвращает ошибку рукопожатия
+// и сертификат, который предъявил сервер.
+func handshake(t *testing.T, serverCfg *tls.Config, roots *x509.CertPool, client *testCert) (*x509.Certificate, error) {
+	t.Helper()
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", serverCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		serverErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	clientCfg := &tls.Config{RootCAs: roots, ServerName: "localhost", MinVersion: tls.VersionTLS12}
+	if client != nil {
+		clientCfg.Certificates = []tls.Certificate{client.tls()}
+	}
+	conn, err := tls.Dial("tcp", ln.Addr().String(), clientCfg)
+	if err != nil {
+		<-serverErr
+		return nil, err
+	}
+	defer conn.Close()
+	// в TLS 1.3 отказ в клиентском сертификате приходит после рукопожатия клиента
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _ = conn.Read(make([]byte, 1))
+	if err := <-serverErr; err != nil {
+		return nil, err
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestServerTLS(t *testing.T) {
+	f := newFiles(t)
+	ca := issue(t, "ca", nil, 0)
+	issue(t, "server", ca, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+
+	r, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := r.ServerConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	got, err := handshake(t, cfg, roots, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Subject.CommonName != "server" {
+		t.Fatalf("unexpected server certificate %q", got.Subject.CommonName)
+	}
+
+	if _, err := r.ServerConfig(true); err == nil {
+		t.Fatal("expected error for client auth without ca_file")
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	f := newFiles(t)
+	ca := issue(t, "ca", nil, 0)
+	ca.write(t, f.ca, "")
+	issue(t, "server", ca, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+
+	r, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key, CAFile: f.ca})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := r.ServerConfig(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	if _, err := handshake(t, cfg, roots, issue(t, "scheduler", ca, x509.ExtKeyUsageClientAuth)); err != nil {
+		t.Fatalf("expected client signed by ca to be accepted, got %v", err)
+	}
+	if _, err := handshake(t, cfg, roots, nil); err == nil {
+		t.Fatal("expected client without certificate to be rejected")
+	}
+	other := issue(t, "other-ca", nil, 0)
+	if _, err := handshake(t, cfg, roots, issue(t, "intruder", other, x509.ExtKeyUsageClientAuth)); err == nil {
+		t.Fatal("expected client signed by unknown ca to be rejected")
+	}
+	if _, err := handshake(t, cfg, roots, issue(t, "server2", ca, x509.ExtKeyUsageServerAuth)); err == nil {
+		t.Fatal("expected certificate without client auth usage to be rejected")
+	}
+}
+
+func TestReload(t *testing.T) {
+	f := newFiles(t)
+	ca := issue(t, "ca", nil, 0)
+	ca.write(t, f.ca, "")
+	issue(t, "server-v1", ca, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+
+	r, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key, CAFile: f.ca})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := r.ServerConfig(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// новый CA и сертификат сервера, подписанный им
+	ca2 := issue(t, "ca2", nil, 0)
+	ca2.write(t, f.ca, "")
+	issue(t, "server-v2", ca2, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca2.cert)
+	got, err := handshake(t, cfg, roots, issue(t, "scheduler", ca2, x509.ExtKeyUsageClientAuth))
+	if err != nil {
+		t.Fatalf("expected reloaded ca and certificate to be used, got %v", err)
+	}
+	if got.Subject.CommonName != "server-v2" {
+		t.Fatalf("expected reloaded certificate, got %q", got.Subject.CommonName)
+	}
+
+	// битый файл не заменяет рабочий сертификат
+	if err := os.WriteFile(f.cert, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected error for invalid certificate")
+	}
+	if got, err := handshake(t, cfg, roots, issue(t, "scheduler", ca2, x509.ExtKeyUsageClientAuth)); err != nil ||
+		got.Subject.CommonName != "server-v2" {
+		t.Fatalf("expected previous certificate to be kept, got %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	f := newFiles(t)
+	ca := issue(t, "ca", nil, 0)
+	issue(t, "server-v1", ca, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+
+	r, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	issue(t, "server-v2", ca, x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+	// время изменения может совпасть с прежним на файловых системах с грубой точностью
+	future := time.Now().Add(time.Minute)
+	for _, p := range []string{f.cert, f.key} {
+		if err := os.Chtimes(p, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		leaf, err := x509.ParseCertificate(r.cert.Load().Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if leaf.Subject.CommonName == "server-v2" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected changed certificate to be reloaded")
+}
+
+func TestNewErrors(t *testing.T) {
+	f := newFiles(t)
+	if _, err := New(logger.New("error"), Config{}); err == nil {
+		t.Fatal("expected error without cert and key")
+	}
+	if _, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key}); err == nil {
+		t.Fatal("expected error for missing files")
+	}
+	issue(t, "server", issue(t, "ca", nil, 0), x509.ExtKeyUsageServerAuth).write(t, f.cert, f.key)
+	writePEM(t, f.ca, "PRIVATE KEY", []byte("not a certificate"))
+	if _, err := New(logger.New("error"), Config{CertFile: f.cert, KeyFile: f.key, CAFile: f.ca}); err == nil {
+		t.Fatal("expected error for ca file without certificates")
+	}
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMetadataPropagation(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: ExporterNone}); err != nil {
+		t.Fatalf("setup failed: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	parent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	out := InjectOutgoing(parent)
+	md, _ := metadata.FromOutgoingContext(out)
+	if got := md.Get("traceparent"); len(got) != 1 {
+		t.Fatalf("expected traceparent in metadata, got %v", md)
+	}
+
+	in := ExtractIncoming(metadata.NewIncomingContext(context.Background(), md))
+	if got := TraceID(in); got != traceID.String() {
+		t.Fatalf("expected trace id %s, got %q", traceID, got)
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Config{Exporter: "jaeger"}); err == nil {
+		t.Fatal("expected error for unknown exporter")
+	}
+}
@@ -11,6 +11,8 @@ CREATE TABLE IF NOT EXISTS events (
     notify_before INTERVAL
 );
 
--- +goose Down
 CREATE INDEX IF NOT EXISTS idx_events_at ON events (at);
 CREATE INDEX IF NOT EXISTS idx_events_user_at ON events (user_id, at);
+
+-- +goose Down
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS event_reminders (
+    event_id UUID NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    offset_before INTERVAL NOT NULL,
+    channel TEXT NOT NULL DEFAULT 'log',
+    PRIMARY KEY (event_id, position)
+);
+
+-- переносим существующие notify_before в первое напоминание
+INSERT INTO event_reminders (event_id, position, offset_before, channel)
+SELECT id, 0, notify_before, 'log'
+FROM events
+WHERE notify_before IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS event_reminders;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- учёт напоминаний, поставленных в очередь и отправленных:
+-- одна строка на (событие, напоминание, наступление события)
+CREATE TABLE IF NOT EXISTS notifications (
+    event_id UUID NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    reminder_offset INTERVAL NOT NULL,
+    channel TEXT NOT NULL,
+    occurrence TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'enqueued',
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id, reminder_offset, channel, occurrence)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- журнал попыток доставки уведомлений на webhook получателей
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    reminder_offset INTERVAL NOT NULL,
+    occurrence TIMESTAMPTZ NOT NULL,
+    url TEXT NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    delivered_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS webhook_deliveries;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- transactional outbox: изменения событий пишутся в одной транзакции с самим изменением,
+-- relay публикует их в очередь и проставляет delivered_at
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    event_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (id) WHERE delivered_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- раньше индексы событий создавались в Down миграции 01 и в базах, где она уже применена,
+-- их нет; IF NOT EXISTS делает миграцию безопасной для баз, созданных исправленной 01
+CREATE INDEX IF NOT EXISTS idx_events_at ON events (at);
+CREATE INDEX IF NOT EXISTS idx_events_user_at ON events (user_id, at);
+
+-- +goose Down
+-- индексы принадлежат миграции 01, откат этой миграции их не трогает
+SELECT 1;
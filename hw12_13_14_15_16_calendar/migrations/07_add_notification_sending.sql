@@ -0,0 +1,7 @@
+-- +goose Up
+-- время, когда рассыльщик взял уведомление на доставку (status = 'sending'):
+-- по нему зависшая из-за падения рассыльщика доставка перехватывается заново
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS sending_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS sending_at;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- доставленные записи outbox удаляются relay по delivered_at
+CREATE INDEX IF NOT EXISTS idx_outbox_delivered ON outbox (delivered_at) WHERE delivered_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_delivered;
@@ -0,0 +1,46 @@
+// Package migrations встраивает SQL миграции в бинарные файлы, чтобы применять их
+// без каталога migrations рядом с программой.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+)
+
+// FS - файлы миграций; каталог внутри FS - ".".
+//
+//go:embed *.sql
+var FS embed.FS
+
+// Dir - каталог миграций внутри FS.
+const Dir = "."
+
+// lockID - ключ advisory lock, под которым применяются миграции.
+const lockID = 7243001
+
+// Up применяет недостающие встроенные миграции. Несколько экземпляров сервиса могут вызвать Up
+// одновременно: миграции выполняет тот, кто первым взял advisory lock, остальные дожидаются его
+// и обнаруживают, что применять нечего.
+func Up(ctx context.Context, db *sql.DB) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID) //nolint:errcheck
+
+	goose.SetBaseFS(FS)
+	defer goose.SetBaseFS(nil)
+	if err := goose.SetDialect("postgres"); err != nil {
+		return err
+	}
+	return goose.Up(db, Dir)
+}
@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	goose.SetBaseFS(FS)
+	defer goose.SetBaseFS(nil)
+
+	all, err := goose.CollectMigrations(Dir, 0, goose.MaxVersion)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	// версии идут подряд с 1: пропуск означает потерянный или переименованный файл
+	for i, m := range all {
+		require.Equal(t, int64(i+1), m.Version, m.Source)
+	}
+}